@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+)
+
+// handleBranchLabel adds labels based on the naming convention of the PR head branch.
+func handleBranchLabel(ctx context.Context, env *Env) error {
+	cfg := env.Config
+	if len(cfg.BranchLabels) == 0 {
+		return nil
+	}
+
+	ref := env.PR.GetHead().GetRef()
+	existing := map[string]bool{}
+	for _, l := range env.PR.Labels {
+		existing[l.GetName()] = true
+	}
+
+	var labels []string
+	for _, label := range branchLabels(ref, cfg) {
+		if !existing[label] {
+			existing[label] = true
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == 0 {
+		loggerFrom(ctx).Infof("No new branch-based labels for branch: %s", ref)
+		return nil
+	}
+
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), labels, "branch-based", cfg)
+}
+
+// branchLabels returns the labels of the BranchLabels rules matching ref, in rule order.
+func branchLabels(ref string, cfg *Config) []string {
+	var labels []string
+	for _, rule := range cfg.BranchLabels {
+		if matchGlob(rule.Key, ref) {
+			labels = append(labels, rule.Value)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// ChangeType labels PRs whose changed files all match its path globs, such as "docs-only"
+// for PRs touching nothing but documentation.
+type ChangeType struct {
+	Label string   `json:"label" yaml:"label"`
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// handleChangeTypeLabel adds the change type label of PRs that touch no code, so trivial
+// PRs can be fast-tracked. The label of a change type the PR no longer matches is removed.
+func handleChangeTypeLabel(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	cfg := env.Config
+	if len(cfg.ChangeTypes) == 0 {
+		return nil
+	}
+
+	label := classifyChangeType(files, cfg)
+
+	var stale []string
+	hasLabel := false
+	for _, ct := range cfg.ChangeTypes {
+		for _, l := range env.PR.Labels {
+			if l.GetName() != ct.Label {
+				continue
+			}
+			if ct.Label == label {
+				hasLabel = true
+			} else {
+				stale = append(stale, ct.Label)
+			}
+		}
+	}
+	removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, stale, cfg)
+
+	if label == "" {
+		loggerFrom(ctx).Infof("PR changes files outside every change type")
+		return nil
+	}
+	if hasLabel {
+		loggerFrom(ctx).Infof("PR already has label: %s", label)
+		return nil
+	}
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{label}, "change-type", cfg)
+}
+
+// classifyChangeType returns the label of the first change type matching every changed
+// file, or "" when the PR changes no files or mixes them with others, such as code.
+func classifyChangeType(files []*github.CommitFile, cfg *Config) string {
+	if len(files) == 0 {
+		return ""
+	}
+	for _, ct := range cfg.ChangeTypes {
+		all := true
+		for _, file := range files {
+			if !matchAnyGlob(ct.Paths, file.GetFilename()) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return ct.Label
+		}
+	}
+	return ""
+}
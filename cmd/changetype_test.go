@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"reflect"
+	"testing"
+)
+
+func TestClassifyChangeType(t *testing.T) {
+	cfg := &Config{ChangeTypes: []ChangeType{
+		{Label: "docs-only", Paths: []string{"**/*.md", "docs/**"}},
+		{Label: "ci-only", Paths: []string{".github/**"}},
+	}}
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{name: "all docs", files: []string{"README.md", "docs/guide/setup.txt"}, want: "docs-only"},
+		{name: "all ci", files: []string{".github/workflows/ci.yml"}, want: "ci-only"},
+		{name: "first match wins", files: []string{".github/CONTRIBUTING.md"}, want: "docs-only"},
+		{name: "docs and code", files: []string{"README.md", "cmd/main.go"}, want: ""},
+		{name: "docs and ci", files: []string{"README.md", ".github/workflows/ci.yml"}, want: ""},
+		{name: "no files", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []*github.CommitFile
+			for _, name := range tt.files {
+				files = append(files, changedFile(name, 1))
+			}
+			if got := classifyChangeType(files, cfg); got != tt.want {
+				t.Errorf("classifyChangeType(%v) = %q, want %q", tt.files, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleChangeTypeLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []string
+		prLabels    []*github.Label
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{name: "docs only", files: []string{"README.md"}, wantAdded: []string{"docs-only"}},
+		{name: "already labeled", files: []string{"README.md"}, prLabels: labels("docs-only")},
+		{name: "code added", files: []string{"README.md", "main.go"}, prLabels: labels("docs-only"), wantRemoved: []string{"docs-only"}},
+		{name: "type changed", files: []string{".github/ci.yml"}, prLabels: labels("docs-only"), wantAdded: []string{"ci-only"}, wantRemoved: []string{"docs-only"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.ChangeTypes = []ChangeType{
+				{Label: "docs-only", Paths: []string{"**/*.md"}},
+				{Label: "ci-only", Paths: []string{".github/**"}},
+			}
+			client := newFakeClient()
+			for _, name := range tt.files {
+				client.pullRequests.files = append(client.pullRequests.files, changedFile(name, 1))
+			}
+
+			if err := handleChangeTypeLabel(context.Background(), testEnv(client.Client, cfg, tt.prLabels), client.pullRequests.files); err != nil {
+				t.Fatalf("handleChangeTypeLabel: %v", err)
+			}
+			if !reflect.DeepEqual(client.issues.added, tt.wantAdded) {
+				t.Errorf("added %v, want %v", client.issues.added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(client.issues.removed, tt.wantRemoved) {
+				t.Errorf("removed %v, want %v", client.issues.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// reportAssignmentCheck publishes the reviewer assignment result as a check run on the PR
+// head commit, so branch protection can require it before merge.
+func reportAssignmentCheck(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest, assigned bool, cfg *Config) {
+	conclusion, summary := cfg.CheckRunFailureConclusion, "No reviewers could be assigned to this pull request."
+	if assigned {
+		conclusion, summary = cfg.CheckRunSuccessConclusion, "Reviewers are assigned to this pull request."
+	}
+	title := "Reviewer assignment"
+	if cfg.DryRun {
+		loggerFrom(ctx).Infof("%s would create check run %s with conclusion: %s", dryRunPrefix, cfg.CheckRunName, conclusion)
+		return
+	}
+
+	err := withRetry(ctx, func() error {
+		_, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, github.CreateCheckRunOptions{
+			Name:       cfg.CheckRunName,
+			HeadSHA:    pr.GetHead().GetSHA(),
+			Status:     github.String("completed"),
+			Conclusion: github.String(conclusion),
+			Output: &github.CheckRunOutput{
+				Title:   &title,
+				Summary: &summary,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to create check run: %v", err)
+	} else {
+		loggerFrom(ctx).Infof("Created check run %s with conclusion: %s", cfg.CheckRunName, conclusion)
+	}
+}
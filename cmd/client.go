@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"golang.org/x/oauth2"
+	"net/url"
+	"strings"
+)
+
+// Client groups the GitHub API services the action uses. Each service is an interface
+// covering only the methods called, so tests can substitute fakes for the network.
+type Client struct {
+	Issues       issuesService
+	PullRequests pullRequestsService
+	Repositories repositoriesService
+	Teams        teamsService
+	Checks       checksService
+	RateLimits   func(ctx context.Context) (*github.RateLimits, *github.Response, error)
+}
+
+// issuesService is the subset of github.IssuesService used by the action.
+type issuesService interface {
+	Get(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	AddLabelsToIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
+	RemoveLabelForIssue(ctx context.Context, owner, repo string, number int, label string) (*github.Response, error)
+	AddAssignees(ctx context.Context, owner, repo string, number int, assignees []string) (*github.Issue, *github.Response, error)
+	ListComments(ctx context.Context, owner, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	EditComment(ctx context.Context, owner, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	ListMilestones(ctx context.Context, owner, repo string, opts *github.MilestoneListOptions) ([]*github.Milestone, *github.Response, error)
+}
+
+// pullRequestsService is the subset of github.PullRequestsService used by the action.
+type pullRequestsService interface {
+	Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error)
+	List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	ListFiles(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
+	ListCommits(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+	ListComments(ctx context.Context, owner, repo string, number int, opts *github.PullRequestListCommentsOptions) ([]*github.PullRequestComment, *github.Response, error)
+	ListReviews(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	ListPullRequestsWithCommit(ctx context.Context, owner, repo, sha string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	RequestReviewers(ctx context.Context, owner, repo string, number int, reviewers github.ReviewersRequest) (*github.PullRequest, *github.Response, error)
+}
+
+// repositoriesService is the subset of github.RepositoriesService used by the action.
+type repositoriesService interface {
+	GetCommit(ctx context.Context, owner, repo, sha string, opts *github.ListOptions) (*github.RepositoryCommit, *github.Response, error)
+	ListCommits(ctx context.Context, owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+	ListCollaborators(ctx context.Context, owner, repo string, opts *github.ListCollaboratorsOptions) ([]*github.User, *github.Response, error)
+	ListContributors(ctx context.Context, owner, repo string, opts *github.ListContributorsOptions) ([]*github.Contributor, *github.Response, error)
+	GetPermissionLevel(ctx context.Context, owner, repo, user string) (*github.RepositoryPermissionLevel, *github.Response, error)
+}
+
+// teamsService is the subset of github.TeamsService used by the action.
+type teamsService interface {
+	ListTeams(ctx context.Context, org string, opts *github.ListOptions) ([]*github.Team, *github.Response, error)
+	ListTeamMembersBySlug(ctx context.Context, org, slug string, opts *github.TeamListTeamMembersOptions) ([]*github.User, *github.Response, error)
+}
+
+// checksService is the subset of github.ChecksService used by the action.
+type checksService interface {
+	CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+}
+
+// defaultAPIURL is the API of github.com, which GitHub-hosted runners set GITHUB_API_URL to.
+const defaultAPIURL = "https://api.github.com"
+
+// newGitHubClient creates a GitHub client using the provided token. A baseURL other than
+// github.com's points the client at the API of a GitHub Enterprise Server instance.
+func newGitHubClient(ctx context.Context, token, baseURL string) (*Client, error) {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	httpClient := oauth2.NewClient(ctx, ts)
+	gh := github.NewClient(httpClient)
+	if baseURL != "" && strings.TrimSuffix(baseURL, "/") != defaultAPIURL {
+		uploadURL, err := enterpriseUploadURL(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		if gh, err = github.NewEnterpriseClient(baseURL, uploadURL, httpClient); err != nil {
+			return nil, err
+		}
+	}
+	return &Client{
+		Issues:       gh.Issues,
+		PullRequests: gh.PullRequests,
+		Repositories: gh.Repositories,
+		Teams:        gh.Teams,
+		Checks:       gh.Checks,
+		RateLimits:   gh.RateLimits,
+	}, nil
+}
+
+// enterpriseUploadURL validates the API base URL of a GitHub Enterprise Server instance,
+// such as "https://github.example.com/api/v3", and returns its upload URL.
+func enterpriseUploadURL(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid GitHub API URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return "", fmt.Errorf("invalid GitHub API URL %q: want an absolute http or https URL, e.g. https://github.example.com/api/v3", baseURL)
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v3") + "/api/uploads/"
+	return u.String(), nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnterpriseUploadURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+		wantErr bool
+	}{
+		{baseURL: "https://github.example.com/api/v3", want: "https://github.example.com/api/uploads/"},
+		{baseURL: "https://github.example.com/api/v3/", want: "https://github.example.com/api/uploads/"},
+		{baseURL: "https://github.example.com", want: "https://github.example.com/api/uploads/"},
+		{baseURL: "github.example.com/api/v3", wantErr: true},
+		{baseURL: "ftp://github.example.com", wantErr: true},
+		{baseURL: "https://", wantErr: true},
+		{baseURL: "https://github.example.com/%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := enterpriseUploadURL(tt.baseURL)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("enterpriseUploadURL(%q) = %q, %v, want %q, error %v", tt.baseURL, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewGitHubClient(t *testing.T) {
+	for _, baseURL := range []string{"", defaultAPIURL, defaultAPIURL + "/", "https://github.example.com/api/v3"} {
+		if _, err := newGitHubClient(context.Background(), "token", baseURL); err != nil {
+			t.Errorf("newGitHubClient(%q): %v", baseURL, err)
+		}
+	}
+	if _, err := newGitHubClient(context.Background(), "token", "not a url"); err == nil {
+		t.Errorf("newGitHubClient accepted a malformed base URL")
+	}
+}
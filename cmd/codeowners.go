@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"strings"
+)
+
+// codeownersPaths are the CODEOWNERS locations in GitHub's resolution order.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is a CODEOWNERS line: a path pattern and the owners of the files it matches.
+// Owners are logins or "org/team" entries, without the leading "@".
+type Rule struct {
+	Pattern string
+	Owners  []string
+}
+
+// parseCodeowners parses the rules of a CODEOWNERS file in file order. Comments and blank
+// lines are skipped, as are email owners, which cannot be requested as reviewers.
+func parseCodeowners(content string) []Rule {
+	var rules []Rule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rule := Rule{Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "@") {
+				rule.Owners = append(rule.Owners, strings.TrimPrefix(owner, "@"))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matches reports whether the rule's pattern matches the file path. Patterns follow the
+// gitignore rules CODEOWNERS uses: a leading "/" or an inner "/" anchors the pattern to the
+// repository root, a pattern without one matches at any depth, and a pattern naming a
+// directory matches everything below it; a trailing "/" matches only directories.
+func (r Rule) matches(path string) bool {
+	dirOnly := strings.HasSuffix(r.Pattern, "/")
+	pattern := strings.TrimSuffix(r.Pattern, "/")
+	if strings.HasPrefix(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	if !dirOnly && matchGlob(pattern, path) {
+		return true
+	}
+	// "docs/*" owns the files directly in docs, but not those in its subdirectories.
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	return !strings.ContainsAny(last, "*?") && matchGlob(pattern+"/**", path)
+}
+
+// codeowners returns the owners of the changed files. As in GitHub, the last matching rule
+// wins for each file; a matching rule without owners leaves the file unowned.
+func codeowners(rules []Rule, files []*github.CommitFile) []string {
+	var owners []string
+	seen := map[string]bool{}
+	for _, file := range files {
+		var match *Rule
+		for i := range rules {
+			if rules[i].matches(file.GetFilename()) {
+				match = &rules[i]
+			}
+		}
+		if match == nil {
+			continue
+		}
+		for _, owner := range match.Owners {
+			if !seen[strings.ToLower(owner)] {
+				seen[strings.ToLower(owner)] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// loadCodeowners reads the first CODEOWNERS file found on the PR base branch. Only a
+// missing file moves on to the next location; any other error, such as a rate limit that
+// outlasted the retries, is returned, so the PR doesn't fall back to random reviewers.
+func loadCodeowners(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest) ([]Rule, error) {
+	opts := &github.RepositoryContentGetOptions{Ref: pr.GetBase().GetRef()}
+	for _, path := range codeownersPaths {
+		var file *github.RepositoryContent
+		err := withRetry(ctx, func() (err error) {
+			file, _, _, err = client.Repositories.GetContents(ctx, owner, repo, path, opts)
+			return err
+		})
+		if isNotFound(err) || (err == nil && file == nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to decode %s: %v", path, err)
+			return nil, nil
+		}
+		loggerFrom(ctx).Infof("Using code owners from %s", path)
+		return parseCodeowners(content), nil
+	}
+	return nil, nil
+}
+
+// codeownerReviewers returns the code owners of the changed files, excluding the author.
+func codeownerReviewers(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest, files []*github.CommitFile) ([]string, error) {
+	rules, err := loadCodeowners(ctx, client, owner, repo, pr)
+	if len(rules) == 0 {
+		return nil, err
+	}
+	return excludeUser(codeowners(rules, files), pr.GetUser().GetLogin()), nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-github/v45/github"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# Default owners
+*       @org/core
+
+*.js    @alice @bob # frontend
+/docs/  @org/docs docs@example.com
+/build/logs/
+`
+	want := []Rule{
+		{Pattern: "*", Owners: []string{"org/core"}},
+		{Pattern: "*.js", Owners: []string{"alice", "bob"}},
+		{Pattern: "/docs/", Owners: []string{"org/docs"}},
+		{Pattern: "/build/logs/"},
+	}
+	if got := parseCodeowners(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCodeowners = %v, want %v", got, want)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "*", path: "a/b/c.go", want: true},
+		{pattern: "*.js", path: "web/app.js", want: true},
+		{pattern: "*.js", path: "web/app.ts", want: false},
+		{pattern: "/docs/", path: "docs/a/b.md", want: true},
+		{pattern: "/docs/", path: "src/docs/b.md", want: false},
+		{pattern: "docs/*", path: "docs/a.md", want: true},
+		{pattern: "docs/*", path: "docs/a/b.md", want: false},
+		{pattern: "apps/", path: "x/apps/y.go", want: true},
+		{pattern: "apps/", path: "apps", want: false},
+		{pattern: "/scripts", path: "scripts/run.sh", want: true},
+		{pattern: "/scripts", path: "tools/scripts/run.sh", want: false},
+		{pattern: "src/**/test", path: "src/a/b/test/x.go", want: true},
+	}
+	for _, tt := range tests {
+		if got := (Rule{Pattern: tt.pattern}).matches(tt.path); got != tt.want {
+			t.Errorf("Rule{%q}.matches(%q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeowners(t *testing.T) {
+	rules := parseCodeowners("* @core\n*.js @alice\n/vendor/\n")
+	files := []*github.CommitFile{
+		{Filename: github.String("main.go")},
+		{Filename: github.String("web/app.js")},
+		{Filename: github.String("vendor/lib.go")},
+		{Filename: github.String("web/util.js")},
+	}
+	want := []string{"core", "alice"}
+	if got := codeowners(rules, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("codeowners = %v, want %v", got, want)
+	}
+}
+
+func TestLoadCodeowners(t *testing.T) {
+	pr := &github.PullRequest{Base: &github.PullRequestBranch{Ref: github.String("main")}}
+
+	client := newFakeClient()
+	client.repositories.contents["docs/CODEOWNERS"] = "* @core"
+	rules, err := loadCodeowners(context.Background(), client.Client, "o", "r", pr)
+	if err != nil || len(rules) != 1 {
+		t.Errorf("loadCodeowners = %v, %v, want the rule of docs/CODEOWNERS", rules, err)
+	}
+
+	client = newFakeClient()
+	rules, err = loadCodeowners(context.Background(), client.Client, "o", "r", pr)
+	if err != nil || rules != nil {
+		t.Errorf("loadCodeowners without CODEOWNERS = %v, %v, want no rules and no error", rules, err)
+	}
+
+	client = newFakeClient()
+	failure := errorResponse(http.StatusInternalServerError)
+	client.repositories.contentsErr = failure
+	if _, err := loadCodeowners(context.Background(), client.Client, "o", "r", pr); !errors.Is(err, failure) {
+		t.Errorf("loadCodeowners = %v, want the read failure", err)
+	}
+	cfg := testConfig(t)
+	client.repositories.collaborators = []string{"dave"}
+	if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), nil); err == nil || len(client.pullRequests.requested) != 0 {
+		t.Errorf("assignDefaultReviewers = %v with requests %v, want an error and no fallback reviewers", err, client.pullRequests.requested)
+	}
+}
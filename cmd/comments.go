@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"strings"
+)
+
+// findExistingComment returns the first issue comment containing marker, or nil if none exists.
+func findExistingComment(ctx context.Context, client *Client, owner, repo string, number int, marker string) (*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		var comments []*github.IssueComment
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			comments, resp, err = client.Issues.ListComments(ctx, owner, repo, number, opts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			if strings.Contains(c.GetBody(), marker) {
+				return c, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// upsertComment creates a comment identified by a hidden marker, or updates it if it already exists.
+func upsertComment(ctx context.Context, client *Client, owner, repo string, number int, marker, body string) error {
+	body = marker + "\n" + body
+	existing, err := findExistingComment(ctx, client, owner, repo, number, marker)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.GetBody() == body {
+			return nil
+		}
+		return withRetry(ctx, func() error {
+			_, _, err := client.Issues.EditComment(ctx, owner, repo, existing.GetID(), &github.IssueComment{Body: &body})
+			return err
+		})
+	}
+	return withRetry(ctx, func() error {
+		_, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &body})
+		return err
+	})
+}
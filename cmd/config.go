@@ -0,0 +1,618 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the optional settings that tune the action's behavior.
+type Config struct {
+	// TitleLabels maps PR title prefixes to one or more labels.
+	TitleLabels map[string][]string
+	// IssueTitleLabels maps issue title prefixes to one or more labels.
+	IssueTitleLabels map[string][]string
+	// TitleAliases maps alternate title prefixes, normalized by normalizeAlias, to the
+	// canonical prefixes of the label maps, e.g. "bugfix" to "fix".
+	TitleAliases map[string]string
+	// TitleSource selects the title to label against: title, body, or commit.
+	TitleSource string
+	// SquashTitleField is the PR body field holding the squash title for the body source.
+	SquashTitleField string
+	// RequireTitleDescription enforces a minimum description length after the title prefix.
+	RequireTitleDescription bool
+	// MinTitleDescription is the minimum description length in characters.
+	MinTitleDescription int
+	// TitleStrict fails the run on an invalid title instead of labeling it.
+	TitleStrict bool
+	// BadTitleLabel is applied to titles that fail validation.
+	BadTitleLabel string
+	// TriageLabel is applied to titles that map to no label; empty disables it.
+	TriageLabel string
+	// RevertNotifyAuthor mentions the author of a reverted change in a comment.
+	RevertNotifyAuthor bool
+	// DefaultAssignees replace the PR author as the default assignees under the configured
+	// assignee strategy.
+	DefaultAssignees []string
+	// AssigneeStrategy picks the default assignees: author or configured.
+	AssigneeStrategy string
+	// FallbackAssignee is assigned when the PR author lacks the write access to be assigned.
+	FallbackAssignee string
+	// PriorityLabels mark a linked issue as high priority.
+	PriorityLabels []string
+	// PriorityAssignees replace the PR author as assignee for high-priority PRs.
+	PriorityAssignees []string
+	// PriorityReviewers replace the default reviewers for high-priority PRs.
+	PriorityReviewers []string
+	// PreferPreviousReviewers re-requests the users who already reviewed the PR before
+	// sampling new reviewers.
+	PreferPreviousReviewers bool
+	// MinContributions limits collaborator candidates to contributors with at least that many
+	// commits. At 1, every collaborator is a candidate.
+	MinContributions int
+	// ReviewerSeed seeds the random reviewer choices. At 0, the PR number is used.
+	ReviewerSeed int
+	// ReviewerStrategy orders sampled reviewer candidates: random, timezone, or balanced.
+	ReviewerStrategy string
+	// ReviewerTimezones maps logins to IANA timezone names.
+	ReviewerTimezones []keyValue
+	// WorkingHoursStart and WorkingHoursEnd bound local working hours, as [start, end).
+	WorkingHoursStart int
+	WorkingHoursEnd   int
+	// ReviewerTiers lists reviewers by priority tier, highest first.
+	ReviewerTiers [][]string
+	// CrossTeamReview excludes candidates who share a team with the author.
+	CrossTeamReview bool
+	// CatchAllReviewers are requested when no routing source selects anyone.
+	CatchAllReviewers []string
+	// SkipDraftReviewers skips reviewer assignment on draft PRs.
+	SkipDraftReviewers bool
+	// SkipDrafts skips every handler on draft PRs.
+	SkipDrafts bool
+	// SkipLabel skips every handler on PRs and issues carrying it, for maintainers curating them by hand.
+	SkipLabel string
+	// MaxReviewers caps the reviewers sampled from history and collaborators; 0 disables
+	// reviewer assignment.
+	MaxReviewers int
+	// IgnoredReviewers are never requested automatically, e.g. people on leave.
+	IgnoredReviewers []string
+	// ReviewerPool and the members of the ReviewerTeams slugs replace the repository
+	// collaborators as the candidates of the last reviewer source.
+	ReviewerPool  []string
+	ReviewerTeams []string
+	// FallbackReviewers are used when the token cannot list collaborators.
+	FallbackReviewers []string
+	// NoReviewersComment enables a PR comment when no reviewers could be found.
+	NoReviewersComment bool
+	// NoReviewersCommentText is the body of that comment.
+	NoReviewersCommentText string
+	// ManagedLabels overrides the set of labels the action may remove or replace.
+	ManagedLabels []string
+	// BranchLabels maps head branch globs (e.g. "feature/*") to labels, in evaluation order.
+	BranchLabels []keyValue
+	// BranchMilestones maps base branches or globs (e.g. "release/1.4") to milestone titles.
+	BranchMilestones []keyValue
+	// Pipeline lists the handlers to run, in order.
+	Pipeline []string
+	// SizeBuckets map the number of changed lines to D-n labels.
+	SizeBuckets []Bucket
+	// SizeFileWeight adds this many lines to the D-n size per changed file.
+	SizeFileWeight int
+	// SizeIgnorePaths are globs of generated or vendored files left out of the size.
+	SizeIgnorePaths []string
+	// SizeLabelUpdate replaces a stale D-n label instead of keeping the first one applied.
+	// It is always enabled for `synchronize` events.
+	SizeLabelUpdate bool
+	// EventHandlers maps webhook actions to the handlers run for them.
+	EventHandlers map[string][]string
+	// EffortBuckets map the review size to estimated review time labels.
+	EffortBuckets []Bucket
+	// EffortFileWeight adds this many lines to the review size per changed file.
+	EffortFileWeight int
+	// DocsSourcePaths are globs of user-facing code that should come with documentation.
+	DocsSourcePaths []string
+	// DocsPaths are globs of documentation files.
+	DocsPaths []string
+	// ChangeTypes label PRs whose changed files all match one of them, tried in order.
+	ChangeTypes []ChangeType
+	// NeedsDocsLabel is applied when source paths change without documentation.
+	NeedsDocsLabel string
+	// SemverLabels enables labeling version bumps found in manifests.
+	SemverLabels bool
+	// VersionManifests locate the version field in changed files.
+	VersionManifests []VersionManifest
+	// SemverLabelPrefix is prepended to major, minor, or patch.
+	SemverLabelPrefix string
+	// TemplateQuestion is the PR template question whose answer drives labels and reviewer counts.
+	TemplateQuestion string
+	// TemplateAnswerLabels maps answers to labels.
+	TemplateAnswerLabels []keyValue
+	// TemplateAnswerReviewers maps answers to the number of reviewers to request.
+	TemplateAnswerReviewers []keyValue
+	// TemplateIncompleteLabel is applied when the question is missing or unanswered.
+	TemplateIncompleteLabel string
+	// SkipConflictedReviewers labels PRs with merge conflicts with NeedsRebaseLabel instead of
+	// requesting reviewers.
+	SkipConflictedReviewers bool
+	NeedsRebaseLabel        string
+	// NeedsWorkThreshold is the number of review comment threads that marks a PR as
+	// needing work; 0 disables the label.
+	NeedsWorkThreshold int
+	// NeedsWorkLabel is applied at or above the threshold.
+	NeedsWorkLabel string
+	// TeamRoutes route reviews to teams by changed path.
+	TeamRoutes []TeamRoute
+	// RoundRobinStateIssue is the issue whose body persists round-robin positions.
+	RoundRobinStateIssue int
+	// Codeowners requests the CODEOWNERS owners of the changed files.
+	Codeowners bool
+	// HistoryReviewers prefers reviewers of merged PRs that touched the same files.
+	HistoryReviewers bool
+	// HistoryMaxFiles caps how many changed files are searched for history.
+	HistoryMaxFiles int
+	// HistoryMaxCalls caps the API calls spent on history lookups.
+	HistoryMaxCalls int
+	// CheckRun publishes the reviewer assignment result as a check run.
+	CheckRun bool
+	// CheckRunName is the name of that check run.
+	CheckRunName string
+	// CheckRunSuccessConclusion is the conclusion when reviewers are assigned.
+	CheckRunSuccessConclusion string
+	// CheckRunFailureConclusion is the conclusion when no reviewers are assigned.
+	CheckRunFailureConclusion string
+	// WarnLargePR comments on PRs changing at least LargePRThreshold lines with the
+	// LargePRTopFiles files changing the most lines.
+	WarnLargePR      bool
+	LargePRThreshold int
+	LargePRTopFiles  int
+	// PostSummaryComment posts a sticky PR comment summarizing the changes of each run.
+	PostSummaryComment bool
+	// RunTimeout bounds the whole run; API calls still in flight when it expires are
+	// cancelled. At 0, the run has no timeout.
+	RunTimeout time.Duration
+	// RetryAttempts is the number of attempts made for GitHub calls hitting a rate limit.
+	RetryAttempts int
+	// DryRun logs the labels, assignees, reviewers, and comments the action would apply
+	// instead of applying them. Read calls still run.
+	DryRun bool
+	// Reconcile sweeps all open PRs instead of processing PR_NUMBER.
+	Reconcile bool
+	// ReconcileHandlers are the features run on each PR during a sweep.
+	ReconcileHandlers []string
+	// ReconcileMinRateRemaining pauses the sweep until reset when fewer API requests remain.
+	ReconcileMinRateRemaining int
+}
+
+// keyValue is a single entry of an ordered mapping.
+type keyValue struct {
+	Key   string
+	Value string
+}
+
+// fileConfig is the repository config file. Its label maps are merged over the built-in
+// defaults; the TITLE_LABELS and ISSUE_TITLE_LABELS environment variables still take
+// precedence over it, as do the environment variables of its other settings.
+type fileConfig struct {
+	TitleLabels      map[string]labelList `yaml:"titleLabels"`
+	IssueTitleLabels map[string]labelList `yaml:"issueTitleLabels"`
+	Aliases          map[string]string    `yaml:"aliases"`
+	TriageLabel      string               `yaml:"triageLabel"`
+	SizeLabels       []Bucket             `yaml:"sizeLabels"`
+	SizeIgnorePaths  []string             `yaml:"sizeIgnorePaths"`
+	SizeFileWeight   int                  `yaml:"sizeFileWeight"`
+	WarnLargePR      bool                 `yaml:"warnLargePR"`
+	ChangeTypeLabels []ChangeType         `yaml:"changeTypeLabels"`
+	IgnoredReviewers []string             `yaml:"ignoredReviewers"`
+	TeamRoutes       []TeamRoute          `yaml:"teamRoutes"`
+	BranchMilestones map[string]string    `yaml:"branchMilestones"`
+	ReviewerPool     []string             `yaml:"reviewerPool"`
+	ReviewerTeams    []string             `yaml:"reviewerTeams"`
+	// MaxReviewers, SkipDraftReviewers, SkipLabel, the large PR settings, and MinContributions
+	// are pointers so that explicit zero values override the defaults.
+	MaxReviewers            *int     `yaml:"maxReviewers"`
+	SkipDraftReviewers      *bool    `yaml:"skipDraftReviewers"`
+	PostSummaryComment      bool     `yaml:"postSummaryComment"`
+	PreferPreviousReviewers bool     `yaml:"preferPreviousReviewers"`
+	FallbackAssignee        string   `yaml:"fallbackAssignee"`
+	DefaultAssignees        []string `yaml:"defaultAssignees"`
+	AssigneeStrategy        string   `yaml:"assigneeStrategy"`
+	SkipLabel               *string  `yaml:"skipLabel"`
+	LargePRThreshold        *int     `yaml:"largePRThreshold"`
+	LargePRTopFiles         *int     `yaml:"largePRTopFiles"`
+	MinContributions        *int     `yaml:"minContributions"`
+}
+
+// defaultConfigPath is the config file read from the repository checkout unless CONFIG_PATH is set.
+const defaultConfigPath = ".github/auto-assign.yml"
+
+// defaultRunTimeout bounds a run unless RUN_TIMEOUT is set.
+const defaultRunTimeout = 2 * time.Minute
+
+const defaultNoReviewersCommentText = "No reviewers could be assigned automatically. A maintainer should request reviewers manually."
+
+// defaultTitleLabels is the built-in prefix to label mapping for pull requests.
+var defaultTitleLabels = map[string][]string{
+	"feat":     {"enhancement"},
+	"fix":      {"bug"},
+	"docs":     {"documentation"},
+	"style":    {"style"},
+	"refactor": {"refactor"},
+	"perf":     {"performance"},
+	"test":     {"test"},
+	"chore":    {"chore"},
+	"revert":   {"revert"},
+}
+
+// defaultIssueTitleLabels is the built-in prefix to label mapping for issues.
+// It differs from the PR mapping where an issue expresses a request rather than a change.
+var defaultIssueTitleLabels = map[string][]string{
+	"feat":     {"feature-request"},
+	"fix":      {"bug"},
+	"docs":     {"documentation"},
+	"style":    {"style"},
+	"refactor": {"refactor"},
+	"perf":     {"performance"},
+	"test":     {"test"},
+	"chore":    {"chore"},
+}
+
+// defaultTitleAliases are the built-in alternate spellings of the default title prefixes.
+var defaultTitleAliases = map[string]string{
+	"feature":       "feat",
+	"bugfix":        "fix",
+	"hotfix":        "fix",
+	"doc":           "docs",
+	"documentation": "docs",
+}
+
+// loadConfig builds the configuration from the config file at path and the environment.
+// A missing file is not an error; the built-in defaults are used instead.
+func loadConfig(path string) (*Config, error) {
+	var file fileConfig
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		defaultLogger().Infof("Loaded config from %s", path)
+	}
+	return configFromEnv(file), nil
+}
+
+// configFromEnv builds the configuration from environment variables, on top of the
+// settings read from the config file.
+func configFromEnv(file fileConfig) *Config {
+	cfg := &Config{
+		TitleLabels:      mergeLabels(defaultTitleLabels, fileLabels(file.TitleLabels), envLabels("TITLE_LABELS")),
+		IssueTitleLabels: mergeLabels(defaultIssueTitleLabels, fileLabels(file.IssueTitleLabels), envLabels("ISSUE_TITLE_LABELS")),
+		TitleAliases:     mergeAliases(defaultTitleAliases, file.Aliases, envPairs("TITLE_ALIASES")),
+
+		TitleSource:      envString("TITLE_SOURCE", titleSourceTitle),
+		SquashTitleField: envString("SQUASH_TITLE_FIELD", "Squash title"),
+
+		RequireTitleDescription: envBool("REQUIRE_TITLE_DESCRIPTION", false),
+		MinTitleDescription:     envInt("TITLE_MIN_DESCRIPTION_LENGTH", 10),
+		TitleStrict:             envBool("TITLE_STRICT", false),
+		BadTitleLabel:           envString("BAD_TITLE_LABEL", "bad-title"),
+		TriageLabel:             envString("TRIAGE_LABEL", file.TriageLabel),
+
+		RevertNotifyAuthor: envBool("REVERT_NOTIFY_AUTHOR", false),
+
+		FallbackAssignee: envString("FALLBACK_ASSIGNEE", file.FallbackAssignee),
+		DefaultAssignees: envList("DEFAULT_ASSIGNEES", file.DefaultAssignees),
+		AssigneeStrategy: envString("ASSIGNEE_STRATEGY", file.AssigneeStrategy),
+
+		PriorityLabels:    envList("PRIORITY_LABELS", []string{"P0", "priority:high"}),
+		PriorityAssignees: envList("PRIORITY_ASSIGNEES", nil),
+		PriorityReviewers: envList("PRIORITY_REVIEWERS", nil),
+
+		ReviewerStrategy:  envString("REVIEWER_STRATEGY", strategyRandom),
+		ReviewerSeed:      envInt("REVIEWER_SEED", 0),
+		MinContributions:  envInt("MIN_CONTRIBUTIONS", orDefaultInt(file.MinContributions, 1)),
+		ReviewerTimezones: envPairs("REVIEWER_TIMEZONES"),
+		WorkingHoursStart: envInt("WORKING_HOURS_START", 9),
+		WorkingHoursEnd:   envInt("WORKING_HOURS_END", 17),
+
+		PreferPreviousReviewers: envBool("PREFER_PREVIOUS_REVIEWERS", file.PreferPreviousReviewers),
+
+		ReviewerTiers:   envJSON[[][]string]("REVIEWER_TIERS"),
+		CrossTeamReview: envBool("CROSS_TEAM_REVIEW", false),
+
+		CatchAllReviewers: envList("CATCH_ALL_REVIEWERS", nil),
+		FallbackReviewers: envList("FALLBACK_REVIEWERS", nil),
+		IgnoredReviewers:  envList("IGNORED_REVIEWERS", file.IgnoredReviewers),
+		ReviewerPool:      envList("REVIEWER_POOL", file.ReviewerPool),
+		ReviewerTeams:     envList("REVIEWER_TEAMS", file.ReviewerTeams),
+		MaxReviewers:      envInt("MAX_REVIEWERS", orDefaultInt(file.MaxReviewers, 10)),
+
+		SkipDraftReviewers: envBool("SKIP_DRAFT_REVIEWERS", orDefaultBool(file.SkipDraftReviewers, true)),
+		SkipDrafts:         envBool("SKIP_DRAFTS", false),
+		SkipLabel:          envString("SKIP_LABEL", orDefaultString(file.SkipLabel, "skip-auto-assign")),
+
+		NoReviewersComment:     envBool("NO_REVIEWERS_COMMENT", false),
+		NoReviewersCommentText: envString("NO_REVIEWERS_COMMENT_TEXT", defaultNoReviewersCommentText),
+
+		BranchLabels:     envPairs("BRANCH_LABELS"),
+		BranchMilestones: orDefault(envPairs("BRANCH_MILESTONES"), sortedPairs(file.BranchMilestones)),
+		ManagedLabels:    envList("MANAGED_LABELS", nil),
+
+		SizeBuckets:     envJSONOr("SIZE_LABELS", orDefault(file.SizeLabels, defaultSizeBuckets)),
+		SizeIgnorePaths: envList("SIZE_IGNORE_PATHS", file.SizeIgnorePaths),
+		SizeFileWeight:  envInt("SIZE_FILE_WEIGHT", file.SizeFileWeight),
+
+		Pipeline:        envList("PIPELINE", defaultPipeline),
+		SizeLabelUpdate: envBool("SIZE_LABEL_UPDATE", false),
+		EventHandlers:   envJSONOr("EVENT_HANDLERS", defaultEventHandlers),
+
+		EffortBuckets:    envJSON[[]Bucket]("EFFORT_LABELS"),
+		EffortFileWeight: envInt("EFFORT_FILE_WEIGHT", 0),
+
+		DocsSourcePaths: envList("DOCS_SOURCE_PATHS", nil),
+		DocsPaths:       envList("DOCS_PATHS", []string{"**/*.md", "docs/**"}),
+		NeedsDocsLabel:  envString("NEEDS_DOCS_LABEL", "needs-docs"),
+		ChangeTypes:     envJSONOr("CHANGE_TYPE_LABELS", file.ChangeTypeLabels),
+
+		SemverLabels:      envBool("SEMVER_LABELS", false),
+		VersionManifests:  envJSONOr("SEMVER_MANIFESTS", defaultVersionManifests),
+		SemverLabelPrefix: envString("SEMVER_LABEL_PREFIX", "semver:"),
+
+		TemplateQuestion:        envString("TEMPLATE_QUESTION", ""),
+		TemplateAnswerLabels:    envPairs("TEMPLATE_ANSWER_LABELS"),
+		TemplateAnswerReviewers: envPairs("TEMPLATE_ANSWER_REVIEWERS"),
+		TemplateIncompleteLabel: envString("TEMPLATE_INCOMPLETE_LABEL", "template-incomplete"),
+
+		SkipConflictedReviewers: envBool("SKIP_CONFLICTED_REVIEWERS", false),
+		NeedsRebaseLabel:        envString("NEEDS_REBASE_LABEL", "needs-rebase"),
+
+		NeedsWorkThreshold: envInt("NEEDS_WORK_THRESHOLD", 0),
+		NeedsWorkLabel:     envString("NEEDS_WORK_LABEL", "needs-work"),
+
+		TeamRoutes:           envJSONOr("TEAM_ROUTES", file.TeamRoutes),
+		RoundRobinStateIssue: envInt("ROUND_ROBIN_STATE_ISSUE", 0),
+
+		Codeowners: envBool("CODEOWNERS_REVIEWERS", true),
+
+		HistoryReviewers: envBool("HISTORY_REVIEWERS", false),
+		HistoryMaxFiles:  envInt("HISTORY_MAX_FILES", 5),
+		HistoryMaxCalls:  envInt("HISTORY_MAX_CALLS", 30),
+
+		CheckRun:                  envBool("CHECK_RUN", false),
+		CheckRunName:              envString("CHECK_RUN_NAME", "auto-assign"),
+		CheckRunSuccessConclusion: envString("CHECK_RUN_SUCCESS_CONCLUSION", "success"),
+		CheckRunFailureConclusion: envString("CHECK_RUN_FAILURE_CONCLUSION", "neutral"),
+
+		WarnLargePR:      envBool("WARN_LARGE_PR", file.WarnLargePR),
+		LargePRThreshold: envInt("LARGE_PR_THRESHOLD", orDefaultInt(file.LargePRThreshold, 500)),
+		LargePRTopFiles:  envInt("LARGE_PR_TOP_FILES", orDefaultInt(file.LargePRTopFiles, 5)),
+
+		PostSummaryComment: envBool("POST_SUMMARY_COMMENT", file.PostSummaryComment),
+
+		RunTimeout:    envDuration("RUN_TIMEOUT", defaultRunTimeout),
+		RetryAttempts: envInt("RETRY_ATTEMPTS", defaultRetryAttempts),
+		DryRun:        envBool("DRY_RUN", false),
+
+		Reconcile:                 envBool("RECONCILE", false),
+		ReconcileHandlers:         envList("RECONCILE_HANDLERS", []string{handlerSize, handlerAssignee}),
+		ReconcileMinRateRemaining: envInt("RECONCILE_MIN_RATE_REMAINING", 100),
+	}
+	// Sweeps pause until the rate limit resets, so only an explicit RUN_TIMEOUT bounds them.
+	if cfg.Reconcile && os.Getenv("RUN_TIMEOUT") == "" {
+		cfg.RunTimeout = 0
+	}
+	return cfg
+}
+
+// mergeLabels returns a copy of defaults with each set of overrides applied on top, in order.
+// Override prefixes are lowercased.
+func mergeLabels(defaults map[string][]string, overrides ...map[string][]string) map[string][]string {
+	merged := make(map[string][]string, len(defaults))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for _, o := range overrides {
+		for k, v := range o {
+			merged[strings.ToLower(k)] = v
+		}
+	}
+	return merged
+}
+
+// orDefault returns value, or def when value is empty.
+func orDefault[T any](value, def []T) []T {
+	if len(value) == 0 {
+		return def
+	}
+	return value
+}
+
+// orDefaultInt returns *value, or def when value is nil.
+func orDefaultInt(value *int, def int) int {
+	if value == nil {
+		return def
+	}
+	return *value
+}
+
+// orDefaultBool returns *value, or def when value is nil.
+func orDefaultBool(value *bool, def bool) bool {
+	if value == nil {
+		return def
+	}
+	return *value
+}
+
+// orDefaultString returns *value, or def when value is nil.
+func orDefaultString(value *string, def string) string {
+	if value == nil {
+		return def
+	}
+	return *value
+}
+
+// labelList is one or more labels. In the config file it may be written as a single label
+// or as a list.
+type labelList []string
+
+// mergeAliases returns the title aliases of defaults, overridden by those of the config file
+// and then the environment. Aliases are keyed by normalizeAlias and their canonical prefixes
+// are lowercased.
+func mergeAliases(defaults, file map[string]string, env []keyValue) map[string]string {
+	merged := make(map[string]string, len(defaults))
+	for k, v := range defaults {
+		merged[normalizeAlias(k)] = strings.ToLower(v)
+	}
+	for k, v := range file {
+		merged[normalizeAlias(k)] = strings.ToLower(strings.TrimSpace(v))
+	}
+	for _, pair := range env {
+		merged[normalizeAlias(pair.Key)] = strings.ToLower(pair.Value)
+	}
+	return merged
+}
+
+// fileLabels converts the label map of the config file.
+func fileLabels(labels map[string]labelList) map[string][]string {
+	converted := make(map[string][]string, len(labels))
+	for k, v := range labels {
+		converted[k] = v
+	}
+	return converted
+}
+
+// UnmarshalYAML accepts both a scalar and a sequence.
+func (l *labelList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = labelList{value.Value}
+		return nil
+	}
+	var labels []string
+	if err := value.Decode(&labels); err != nil {
+		return err
+	}
+	*l = labels
+	return nil
+}
+
+// envList reads a comma-separated list from the environment, returning def when unset.
+func envList(name string, def []string) []string {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return def
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envPairs reads a comma-separated list of key=value pairs from the environment,
+// preserving their order. Malformed entries are ignored.
+func envPairs(name string) []keyValue {
+	var pairs []keyValue
+	for _, item := range envList(name, nil) {
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			continue
+		}
+		pairs = append(pairs, keyValue{Key: key, Value: value})
+	}
+	return pairs
+}
+
+// sortedPairs returns the entries of m as pairs sorted by key, so that glob rules read from
+// the config file are tried in a stable order.
+func sortedPairs(m map[string]string) []keyValue {
+	var pairs []keyValue
+	for k, v := range m {
+		pairs = append(pairs, keyValue{Key: k, Value: v})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	return pairs
+}
+
+// envLabels reads prefix=label pairs from the environment. A prefix maps to several labels
+// separated by "|", e.g. fix=bug|needs-review.
+func envLabels(name string) map[string][]string {
+	labels := map[string][]string{}
+	for _, pair := range envPairs(name) {
+		for _, l := range strings.Split(pair.Value, "|") {
+			if l = strings.TrimSpace(l); l != "" {
+				labels[pair.Key] = append(labels[pair.Key], l)
+			}
+		}
+	}
+	return labels
+}
+
+// envJSON decodes a JSON value from the environment, returning the zero value when unset.
+// Malformed values are fatal so that a typo doesn't silently disable a feature.
+func envJSON[T any](name string) T {
+	var value T
+	raw := os.Getenv(name)
+	if raw == "" {
+		return value
+	}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		defaultLogger().Fatalf("Invalid %s: %v", name, err)
+	}
+	return value
+}
+
+// envJSONOr decodes a JSON value from the environment, returning def when unset.
+func envJSONOr[T any](name string, def T) T {
+	if os.Getenv(name) == "" {
+		return def
+	}
+	return envJSON[T](name)
+}
+
+// envString reads a string from the environment, returning def when unset or empty.
+func envString(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
+
+// envBool reads a boolean from the environment, returning def when unset or unparsable.
+func envBool(name string, def bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envDuration reads a duration such as "90s" or "5m" from the environment, returning def
+// when unset or unparsable.
+func envDuration(name string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envInt reads an integer from the environment, returning def when unset or unparsable.
+func envInt(name string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto-assign.yml")
+	content := `titleLabels:
+  fix: [bug, needs-review]
+  ":sparkles:": enhancement
+triageLabel: needs-triage
+maxReviewers: 0
+aliases:
+  Bug_Fix: FIX
+  feature: chore
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TITLE_LABELS", "build=build|ci")
+	t.Setenv("TITLE_ALIASES", "feature=feat,ops=chore")
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	wantLabels := map[string][]string{
+		"fix":        {"bug", "needs-review"},
+		":sparkles:": {"enhancement"},
+		"build":      {"build", "ci"},
+		"feat":       {"enhancement"},
+	}
+	for prefix, want := range wantLabels {
+		if got := cfg.TitleLabels[prefix]; !reflect.DeepEqual(got, want) {
+			t.Errorf("TitleLabels[%q] = %v, want %v", prefix, got, want)
+		}
+	}
+	wantAliases := map[string]string{"bugfix": "fix", "feature": "feat", "ops": "chore", "documentation": "docs"}
+	for alias, want := range wantAliases {
+		if got := cfg.TitleAliases[alias]; got != want {
+			t.Errorf("TitleAliases[%q] = %q, want %q", alias, got, want)
+		}
+	}
+	if cfg.TriageLabel != "needs-triage" {
+		t.Errorf("TriageLabel = %q, want needs-triage", cfg.TriageLabel)
+	}
+	if cfg.MaxReviewers != 0 {
+		t.Errorf("MaxReviewers = %d, want 0", cfg.MaxReviewers)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	cfg, err := loadConfig(filepath.Join(t.TempDir(), "missing.yml"))
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.TitleLabels, defaultTitleLabels) {
+		t.Errorf("TitleLabels = %v, want the defaults", cfg.TitleLabels)
+	}
+	if cfg.MaxReviewers != 10 || !cfg.SkipDraftReviewers {
+		t.Errorf("MaxReviewers = %d, SkipDraftReviewers = %v, want 10 and true", cfg.MaxReviewers, cfg.SkipDraftReviewers)
+	}
+}
+
+func TestLoadConfigInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto-assign.yml")
+	if err := os.WriteFile(path, []byte("titleLabels: ["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil {
+		t.Error("loadConfig accepted malformed YAML")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		runTimeout string
+		reconcile  string
+		want       time.Duration
+	}{
+		{name: "default", want: defaultRunTimeout},
+		{name: "set", runTimeout: "5m", want: 5 * time.Minute},
+		{name: "disabled", runTimeout: "0", want: 0},
+		{name: "unparsable", runTimeout: "soon", want: defaultRunTimeout},
+		{name: "reconcile", reconcile: "true", want: 0},
+		{name: "reconcile with timeout", runTimeout: "30m", reconcile: "true", want: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RUN_TIMEOUT", tt.runTimeout)
+			t.Setenv("RECONCILE", tt.reconcile)
+			if got := configFromEnv(fileConfig{}).RunTimeout; got != tt.want {
+				t.Errorf("RunTimeout = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
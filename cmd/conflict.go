@@ -0,0 +1,28 @@
+package main
+
+import "context"
+
+// handleMergeConflict labels a PR with merge conflicts with NeedsRebaseLabel and reports
+// that it has conflicts, so that reviewers aren't requested before it is rebased. Once the
+// conflicts are resolved, the label is removed. GitHub computes mergeability in the
+// background after a push; until it has, the PR is neither labeled nor reported.
+func handleMergeConflict(ctx context.Context, env *Env) (bool, error) {
+	cfg := env.Config
+	if env.PR.Mergeable == nil {
+		loggerFrom(ctx).Infof("Mergeability of the PR is not determined yet, requesting reviewers anyway")
+		return false, nil
+	}
+	if env.PR.GetMergeable() || env.PR.GetMergeableState() != "dirty" {
+		removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, []string{cfg.NeedsRebaseLabel}, cfg)
+		return false, nil
+	}
+
+	loggerFrom(ctx).Infof("PR has merge conflicts, not requesting reviewers until it is rebased")
+	for _, l := range env.PR.Labels {
+		if l.GetName() == cfg.NeedsRebaseLabel {
+			loggerFrom(ctx).Infof("PR already has label: %s", cfg.NeedsRebaseLabel)
+			return true, nil
+		}
+	}
+	return true, addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{cfg.NeedsRebaseLabel}, "needs-rebase", cfg)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// handleNeedsDocsLabel adds a needs-docs label when the PR changes source paths that
+// warrant documentation but touches none of the documentation paths.
+func handleNeedsDocsLabel(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	cfg := env.Config
+	if len(cfg.DocsSourcePaths) == 0 {
+		return nil
+	}
+
+	if !needsDocs(files, cfg.DocsSourcePaths, cfg.DocsPaths) {
+		loggerFrom(ctx).Infof("PR does not need documentation changes")
+		removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, []string{cfg.NeedsDocsLabel}, cfg)
+		return nil
+	}
+
+	for _, l := range env.PR.Labels {
+		if l.GetName() == cfg.NeedsDocsLabel {
+			loggerFrom(ctx).Infof("PR already has label: %s", cfg.NeedsDocsLabel)
+			return nil
+		}
+	}
+
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{cfg.NeedsDocsLabel}, "needs-docs", cfg)
+}
+
+// needsDocs reports whether any file matches sourcePaths while none matches docsPaths.
+func needsDocs(files []*github.CommitFile, sourcePaths, docsPaths []string) bool {
+	touchesSource := false
+	for _, file := range files {
+		if matchAnyGlob(docsPaths, file.GetFilename()) {
+			return false
+		}
+		if matchAnyGlob(sourcePaths, file.GetFilename()) {
+			touchesSource = true
+		}
+	}
+	return touchesSource
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// dryRunPrefix marks log lines describing a write skipped by DRY_RUN.
+const dryRunPrefix = "[dry-run]"
+
+// addLabels adds labels to a PR or issue, or only logs them under DRY_RUN. kind names the
+// labels in log and error messages, e.g. "D-n" or "semver".
+func addLabels(ctx context.Context, client *Client, owner, repo string, number int, labels []string, kind string, cfg *Config) error {
+	if cfg.DryRun {
+		for _, l := range labels {
+			loggerFrom(ctx).With("label", l).Infof("%s would add %s label: %s", dryRunPrefix, kind, l)
+		}
+		summaryFrom(ctx).addLabels(labels...)
+		return nil
+	}
+	err := withRetry(ctx, func() error {
+		_, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("add %s label: %w", kind, err)
+	}
+	loggerFrom(ctx).With("labels", labels).Infof("Added %s label: %s", kind, strings.Join(labels, ", "))
+	summaryFrom(ctx).addLabels(labels...)
+	return nil
+}
+
+// addAssignees assigns users to a PR or issue, or only logs them under DRY_RUN.
+func addAssignees(ctx context.Context, client *Client, owner, repo string, number int, assignees []string, cfg *Config) error {
+	if cfg.DryRun {
+		for _, a := range assignees {
+			loggerFrom(ctx).With("assignee", a).Infof("%s would add assignee: %s", dryRunPrefix, a)
+		}
+		summaryFrom(ctx).addAssignees(assignees...)
+		return nil
+	}
+	err := withRetry(ctx, func() error {
+		_, _, err := client.Issues.AddAssignees(ctx, owner, repo, number, assignees)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("add default assignee: %w", err)
+	}
+	loggerFrom(ctx).With("assignees", assignees).Infof("Default assignee (%s) added", strings.Join(assignees, ", "))
+	summaryFrom(ctx).addAssignees(assignees...)
+	return nil
+}
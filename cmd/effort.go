@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// Bucket maps a size range to a label. A bucket applies when the size is below MaxChanges;
+// a MaxChanges of 0 makes it a catch-all.
+type Bucket struct {
+	MaxChanges int    `json:"maxChanges" yaml:"maxChanges"`
+	Label      string `json:"label" yaml:"label"`
+}
+
+// defaultSizeBuckets are the D-n labels applied when no size buckets are configured.
+var defaultSizeBuckets = []Bucket{
+	{MaxChanges: 200, Label: "D-3"},
+	{MaxChanges: 500, Label: "D-5"},
+	{Label: "D-7"},
+}
+
+// selectSizeLabel returns the label of the first bucket that changes falls into, or "" when
+// none does.
+func selectSizeLabel(changes int, buckets []Bucket) string {
+	for _, b := range buckets {
+		if b.MaxChanges == 0 || changes < b.MaxChanges {
+			return b.Label
+		}
+	}
+	return ""
+}
+
+// sizeScore sums the additions and deletions of the changed files and adds fileWeight per
+// changed file, since many small edits across files take longer to review than their line
+// count suggests. Files matching one of the ignore globs, such as lockfiles or vendored code,
+// are skipped.
+func sizeScore(files []*github.CommitFile, ignore []string, fileWeight int) int {
+	total := 0
+	for _, file := range files {
+		if matchAnyGlob(ignore, file.GetFilename()) {
+			continue
+		}
+		total += file.GetAdditions() + file.GetDeletions() + fileWeight
+	}
+	return total
+}
+
+// handleEffortLabel adds a label estimating the review time of the PR, such as "~1h".
+// The size is the number of changed lines plus EffortFileWeight per changed file, leaving
+// out files matching SizeIgnorePaths.
+func handleEffortLabel(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	cfg := env.Config
+	if len(cfg.EffortBuckets) == 0 {
+		return nil
+	}
+
+	score := sizeScore(files, cfg.SizeIgnorePaths, cfg.EffortFileWeight)
+
+	label := selectSizeLabel(score, cfg.EffortBuckets)
+	if label == "" {
+		loggerFrom(ctx).Infof("No effort bucket for size %d", score)
+		return nil
+	}
+
+	var stale []string
+	for _, b := range cfg.EffortBuckets {
+		if b.Label != label {
+			stale = append(stale, b.Label)
+		}
+	}
+	removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, stale, cfg)
+
+	for _, l := range env.PR.Labels {
+		if l.GetName() == label {
+			loggerFrom(ctx).Infof("PR already has label: %s", label)
+			return nil
+		}
+	}
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{label}, "effort", cfg)
+}
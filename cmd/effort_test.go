@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/google/go-github/v45/github"
+	"testing"
+)
+
+func TestSelectSizeLabel(t *testing.T) {
+	buckets := []Bucket{{MaxChanges: 50, Label: "<15min"}, {MaxChanges: 400, Label: "~1h"}, {Label: ">2h"}}
+	tests := []struct {
+		changes int
+		buckets []Bucket
+		want    string
+	}{
+		{changes: 0, buckets: buckets, want: "<15min"},
+		{changes: 49, buckets: buckets, want: "<15min"},
+		{changes: 50, buckets: buckets, want: "~1h"},
+		{changes: 399, buckets: buckets, want: "~1h"},
+		{changes: 10000, buckets: buckets, want: ">2h"},
+		{changes: 10000, buckets: buckets[:2], want: ""},
+		{changes: 199, buckets: defaultSizeBuckets, want: "D-3"},
+		{changes: 200, buckets: defaultSizeBuckets, want: "D-5"},
+		{changes: 500, buckets: defaultSizeBuckets, want: "D-7"},
+		{changes: 10, buckets: nil, want: ""},
+	}
+	for _, tt := range tests {
+		if got := selectSizeLabel(tt.changes, tt.buckets); got != tt.want {
+			t.Errorf("selectSizeLabel(%d, %v) = %q, want %q", tt.changes, tt.buckets, got, tt.want)
+		}
+	}
+}
+
+func TestSizeScore(t *testing.T) {
+	files := []*github.CommitFile{changedFile("a.go", 10), changedFile("b.go", 1), changedFile("go.sum", 500)}
+	ignore := []string{"go.sum"}
+	tests := []struct {
+		name       string
+		files      []*github.CommitFile
+		ignore     []string
+		fileWeight int
+		want       int
+	}{
+		{name: "lines only", files: files, ignore: ignore, want: 11},
+		{name: "weighted files", files: files, ignore: ignore, fileWeight: 20, want: 51},
+		{name: "ignored files carry no weight", files: files[2:], ignore: ignore, fileWeight: 20, want: 0},
+		{name: "nothing ignored", files: files, fileWeight: 1, want: 514},
+		{name: "no files", fileWeight: 20, want: 0},
+	}
+	for _, tt := range tests {
+		if got := sizeScore(tt.files, tt.ignore, tt.fileWeight); got != tt.want {
+			t.Errorf("%s: sizeScore = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
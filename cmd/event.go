@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// defaultEventHandlers limits push events to re-evaluating the size label and the large PR
+// comment, so pushes neither re-request reviewers nor re-label by title. Actions not listed
+// run every handler.
+var defaultEventHandlers = map[string][]string{
+	"synchronize": {handlerSize, handlerLargePR},
+}
+
+// eventAction returns the `action` of the webhook payload that triggered the workflow, such
+// as "opened" or "synchronize", or "" when no payload is available.
+func eventAction() string {
+	path := os.Getenv("GITHUB_EVENT_PATH")
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		defaultLogger().Warnf("Failed to read event payload: %v", err)
+		return ""
+	}
+	var payload struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		defaultLogger().Warnf("Failed to parse event payload: %v", err)
+		return ""
+	}
+	return payload.Action
+}
+
+// handlersForEvent returns the handlers to run for an event action, or nil to run the
+// whole pipeline.
+func handlersForEvent(action string, cfg *Config) []string {
+	if handlers, ok := cfg.EventHandlers[action]; ok {
+		defaultLogger().Infof("Running handlers %v for %q event", handlers, action)
+		return handlers
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	// Tests running in GitHub Actions must not write to the job summary of the workflow.
+	os.Unsetenv("GITHUB_STEP_SUMMARY")
+	os.Exit(m.Run())
+}
+
+// fakeIssues records the writes made through the issues API and serves an issue, milestones,
+// and comments. Methods not overridden panic through the nil embedded interface, flagging
+// unexpected calls.
+type fakeIssues struct {
+	issuesService
+	issue      *github.Issue
+	issueErr   error
+	added      []string
+	removed    []string
+	assignees  []string
+	milestones []*github.Milestone
+	edits      []*github.IssueRequest
+	comments   []*github.IssueComment
+}
+
+func (f *fakeIssues) Get(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error) {
+	if f.issueErr != nil {
+		return nil, nil, f.issueErr
+	}
+	if f.issue == nil {
+		return nil, nil, notFound()
+	}
+	return f.issue, &github.Response{}, nil
+}
+
+func (f *fakeIssues) AddLabelsToIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	f.added = append(f.added, labels...)
+	return nil, &github.Response{}, nil
+}
+
+func (f *fakeIssues) RemoveLabelForIssue(ctx context.Context, owner, repo string, number int, label string) (*github.Response, error) {
+	f.removed = append(f.removed, label)
+	return &github.Response{}, nil
+}
+
+func (f *fakeIssues) AddAssignees(ctx context.Context, owner, repo string, number int, assignees []string) (*github.Issue, *github.Response, error) {
+	f.assignees = append(f.assignees, assignees...)
+	return nil, &github.Response{}, nil
+}
+
+func (f *fakeIssues) ListComments(ctx context.Context, owner, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	return f.comments, &github.Response{}, nil
+}
+
+func (f *fakeIssues) CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	created := &github.IssueComment{ID: github.Int64(int64(len(f.comments) + 1)), Body: comment.Body}
+	f.comments = append(f.comments, created)
+	return created, &github.Response{}, nil
+}
+
+func (f *fakeIssues) EditComment(ctx context.Context, owner, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	for _, c := range f.comments {
+		if c.GetID() == commentID {
+			c.Body = comment.Body
+			return c, &github.Response{}, nil
+		}
+	}
+	return nil, nil, notFound()
+}
+
+func (f *fakeIssues) ListMilestones(ctx context.Context, owner, repo string, opts *github.MilestoneListOptions) ([]*github.Milestone, *github.Response, error) {
+	return f.milestones, &github.Response{}, nil
+}
+
+func (f *fakeIssues) Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	f.edits = append(f.edits, issue)
+	return nil, &github.Response{}, nil
+}
+
+// fakePullRequests serves the changed files of a PR, paginated like GitHub, and its reviews,
+// and records review requests.
+type fakePullRequests struct {
+	pullRequestsService
+	files     []*github.CommitFile
+	fileCalls int
+	reviews   []*github.PullRequestReview
+	requested []github.ReviewersRequest
+}
+
+func (f *fakePullRequests) ListFiles(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+	f.fileCalls++
+	page, perPage := 1, 30
+	if opts != nil && opts.Page > 0 {
+		page = opts.Page
+	}
+	if opts != nil && opts.PerPage > 0 {
+		perPage = opts.PerPage
+	}
+	start, end := (page-1)*perPage, page*perPage
+	resp := &github.Response{}
+	if end < len(f.files) {
+		resp.NextPage = page + 1
+	}
+	return f.files[min(start, len(f.files)):min(end, len(f.files))], resp, nil
+}
+
+func (f *fakePullRequests) ListReviews(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+	return f.reviews, &github.Response{}, nil
+}
+
+func (f *fakePullRequests) RequestReviewers(ctx context.Context, owner, repo string, number int, reviewers github.ReviewersRequest) (*github.PullRequest, *github.Response, error) {
+	f.requested = append(f.requested, reviewers)
+	return nil, &github.Response{}, nil
+}
+
+// fakeRepositories serves collaborators, contributors, permissions, and file contents. Missing files
+// and users answer 404.
+type fakeRepositories struct {
+	repositoriesService
+	collaborators    []string
+	collaboratorsErr error
+	contributions    map[string]int
+	permissions      map[string]string
+	contents         map[string]string
+	contentsErr      error
+}
+
+func (f *fakeRepositories) ListCollaborators(ctx context.Context, owner, repo string, opts *github.ListCollaboratorsOptions) ([]*github.User, *github.Response, error) {
+	if f.collaboratorsErr != nil {
+		return nil, nil, f.collaboratorsErr
+	}
+	var users []*github.User
+	for _, login := range f.collaborators {
+		users = append(users, &github.User{Login: github.String(login), Type: github.String("User")})
+	}
+	return users, &github.Response{}, nil
+}
+
+func (f *fakeRepositories) ListContributors(ctx context.Context, owner, repo string, opts *github.ListContributorsOptions) ([]*github.Contributor, *github.Response, error) {
+	var contributors []*github.Contributor
+	for login, n := range f.contributions {
+		contributors = append(contributors, &github.Contributor{Login: github.String(login), Contributions: github.Int(n)})
+	}
+	return contributors, &github.Response{}, nil
+}
+
+func (f *fakeRepositories) GetPermissionLevel(ctx context.Context, owner, repo, user string) (*github.RepositoryPermissionLevel, *github.Response, error) {
+	level, ok := f.permissions[user]
+	if !ok {
+		level = "none"
+	}
+	return &github.RepositoryPermissionLevel{Permission: github.String(level)}, &github.Response{}, nil
+}
+
+func (f *fakeRepositories) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	if f.contentsErr != nil {
+		return nil, nil, nil, f.contentsErr
+	}
+	content, ok := f.contents[path]
+	if !ok {
+		return nil, nil, nil, notFound()
+	}
+	return &github.RepositoryContent{Content: github.String(content)}, nil, &github.Response{}, nil
+}
+
+// fakeTeams serves team members by slug; unknown teams answer 404.
+type fakeTeams struct {
+	teamsService
+	members map[string][]string
+}
+
+func (f *fakeTeams) ListTeamMembersBySlug(ctx context.Context, org, slug string, opts *github.TeamListTeamMembersOptions) ([]*github.User, *github.Response, error) {
+	logins, ok := f.members[slug]
+	if !ok {
+		return nil, nil, notFound()
+	}
+	var users []*github.User
+	for _, login := range logins {
+		users = append(users, &github.User{Login: github.String(login)})
+	}
+	return users, &github.Response{}, nil
+}
+
+// notFound returns the error of a GitHub 404 response.
+func notFound() error {
+	return errorResponse(http.StatusNotFound)
+}
+
+// errorResponse returns the error of a GitHub response with the given status code.
+func errorResponse(status int) error {
+	resp := &http.Response{StatusCode: status, Request: &http.Request{Method: http.MethodGet, URL: &url.URL{}}}
+	return &github.ErrorResponse{Response: resp, Message: http.StatusText(status)}
+}
+
+// fakeClient bundles the fakes into a Client.
+type fakeClient struct {
+	*Client
+	issues       *fakeIssues
+	pullRequests *fakePullRequests
+	repositories *fakeRepositories
+	teams        *fakeTeams
+}
+
+func newFakeClient() *fakeClient {
+	f := &fakeClient{
+		issues:       &fakeIssues{},
+		pullRequests: &fakePullRequests{},
+		repositories: &fakeRepositories{permissions: map[string]string{}, contents: map[string]string{}},
+		teams:        &fakeTeams{members: map[string][]string{}},
+	}
+	f.Client = &Client{Issues: f.issues, PullRequests: f.pullRequests, Repositories: f.repositories, Teams: f.teams}
+	return f
+}
+
+// testConfig returns the configuration of a run without a config file. CONFIG_PATH is
+// cleared so that a config file of the checkout can't leak into tests.
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	t.Setenv("CONFIG_PATH", "")
+	return configFromEnv(fileConfig{})
+}
+
+// labels builds PR labels from names.
+func labels(names ...string) []*github.Label {
+	var ls []*github.Label
+	for _, n := range names {
+		ls = append(ls, &github.Label{Name: github.String(n)})
+	}
+	return ls
+}
+
+// changedFile builds a changed file with the given number of changed lines.
+func changedFile(name string, changes int) *github.CommitFile {
+	return &github.CommitFile{Filename: github.String(name), Additions: github.Int(changes)}
+}
+
+// testEnv builds an Env for PR #1 by "author" in o/r.
+func testEnv(client *Client, cfg *Config, prLabels []*github.Label) *Env {
+	pr := &github.PullRequest{
+		Number: github.Int(1),
+		User:   &github.User{Login: github.String("author")},
+		Labels: prLabels,
+	}
+	return &Env{Client: client, Owner: "o", Repo: "r", PR: pr, Config: cfg}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"sort"
+)
+
+// historyReviewers returns the authors and reviewers of previously merged PRs that touched
+// the same files as this PR, most frequently involved first. Merged PRs are found through the
+// commit history of each changed path, and API usage is capped by cfg.HistoryMaxFiles and
+// cfg.HistoryMaxCalls.
+func historyReviewers(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest, files []*github.CommitFile, cfg *Config) []string {
+	calls := 0
+	budget := func() bool {
+		if calls >= cfg.HistoryMaxCalls {
+			return false
+		}
+		calls++
+		return true
+	}
+
+	seenCommits := map[string]bool{}
+	seenPRs := map[int]bool{pr.GetNumber(): true}
+	scores := map[string]int{}
+
+	for i, file := range files {
+		if i >= cfg.HistoryMaxFiles || !budget() {
+			break
+		}
+		var commits []*github.RepositoryCommit
+		err := withRetry(ctx, func() (err error) {
+			commits, _, err = client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+				SHA:         pr.GetBase().GetRef(),
+				Path:        file.GetFilename(),
+				ListOptions: github.ListOptions{PerPage: 10},
+			})
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to list commits for %s: %v", file.GetFilename(), err)
+			continue
+		}
+
+		for _, commit := range commits {
+			if seenCommits[commit.GetSHA()] {
+				continue
+			}
+			seenCommits[commit.GetSHA()] = true
+			if !budget() {
+				break
+			}
+			var prs []*github.PullRequest
+			err := withRetry(ctx, func() (err error) {
+				prs, _, err = client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, commit.GetSHA(), nil)
+				return err
+			})
+			if err != nil {
+				loggerFrom(ctx).Warnf("Failed to list PRs for commit %s: %v", commit.GetSHA(), err)
+				continue
+			}
+
+			for _, merged := range prs {
+				if merged.MergedAt == nil || seenPRs[merged.GetNumber()] {
+					continue
+				}
+				seenPRs[merged.GetNumber()] = true
+				scores[merged.GetUser().GetLogin()]++
+				if !budget() {
+					continue
+				}
+				var reviews []*github.PullRequestReview
+				err := withRetry(ctx, func() (err error) {
+					reviews, _, err = client.PullRequests.ListReviews(ctx, owner, repo, merged.GetNumber(), nil)
+					return err
+				})
+				if err != nil {
+					loggerFrom(ctx).Warnf("Failed to list reviews for PR #%d: %v", merged.GetNumber(), err)
+					continue
+				}
+				reviewed := map[string]bool{}
+				for _, review := range reviews {
+					reviewed[review.GetUser().GetLogin()] = true
+				}
+				for login := range reviewed {
+					scores[login]++
+				}
+			}
+		}
+	}
+
+	author := pr.GetUser().GetLogin()
+	var candidates []string
+	for login := range scores {
+		if login != "" && login != author {
+			candidates = append(candidates, login)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if scores[candidates[i]] != scores[candidates[j]] {
+			return scores[candidates[i]] > scores[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	loggerFrom(ctx).Infof("Found %d reviewers from file history using %d API calls", len(candidates), calls)
+	return candidates
+}
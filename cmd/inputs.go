@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ownerPattern matches GitHub user and organization names: letters, digits, and single
+	// hyphens or underscores, not at either end. Enterprise Managed Users have underscores,
+	// as in "login_shortcode".
+	ownerPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[-_]?[A-Za-z0-9])*$`)
+	// repoPattern matches GitHub repository names.
+	repoPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// Event types accepted by EVENT_TYPE.
+const (
+	eventTypePullRequest = "pull_request"
+	eventTypeIssues      = "issues"
+)
+
+// inputs holds the values the workflow passes for the run: the token, the repository, the
+// type of event, and the numbers of the PR and issue, which are 0 when not set, as in
+// reconcile mode.
+type inputs struct {
+	Token       string
+	Owner       string
+	Repo        string
+	EventType   string
+	PRNumber    int
+	IssueNumber int
+}
+
+// parseInputs reads and validates GITHUB_TOKEN, GITHUB_REPOSITORY, EVENT_TYPE, PR_NUMBER,
+// and ISSUE_NUMBER. Without EVENT_TYPE, the event type is issues on `issues` events and
+// pull_request otherwise. On issues events, ISSUE_NUMBER falls back to PR_NUMBER.
+func parseInputs() (inputs, error) {
+	var in inputs
+	in.Token = strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
+	if in.Token == "" {
+		return in, errors.New("GITHUB_TOKEN env not set")
+	}
+	var err error
+	in.Owner, in.Repo, err = parseRepository(os.Getenv("GITHUB_REPOSITORY"))
+	if err != nil {
+		return in, err
+	}
+	switch in.EventType = strings.TrimSpace(os.Getenv("EVENT_TYPE")); in.EventType {
+	case eventTypePullRequest, eventTypeIssues:
+	case "":
+		in.EventType = eventTypePullRequest
+		if os.Getenv("GITHUB_EVENT_NAME") == eventTypeIssues {
+			in.EventType = eventTypeIssues
+		}
+	default:
+		return in, fmt.Errorf("EVENT_TYPE %q is neither %s nor %s", in.EventType, eventTypePullRequest, eventTypeIssues)
+	}
+	if raw := strings.TrimSpace(os.Getenv("PR_NUMBER")); raw != "" {
+		if in.PRNumber, err = parseNumber("PR_NUMBER", raw); err != nil {
+			return in, err
+		}
+	}
+	if in.EventType == eventTypeIssues {
+		in.IssueNumber = in.PRNumber
+		if raw := strings.TrimSpace(os.Getenv("ISSUE_NUMBER")); raw != "" {
+			if in.IssueNumber, err = parseNumber("ISSUE_NUMBER", raw); err != nil {
+				return in, err
+			}
+		}
+	}
+	return in, nil
+}
+
+// parseRepository splits an "owner/repo" string. Surrounding whitespace and slashes are
+// ignored; anything else must be a well-formed owner and repository name.
+func parseRepository(s string) (owner, repo string, err error) {
+	s = strings.Trim(strings.TrimSpace(s), "/")
+	if s == "" {
+		return "", "", errors.New("GITHUB_REPOSITORY env not set")
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("GITHUB_REPOSITORY %q is not in owner/repo format", s)
+	}
+	owner, repo = parts[0], parts[1]
+	if len(owner) > 39 || !ownerPattern.MatchString(owner) {
+		return "", "", fmt.Errorf("GITHUB_REPOSITORY %q has an invalid owner %q", s, owner)
+	}
+	if len(repo) > 100 || !repoPattern.MatchString(repo) || repo == "." || repo == ".." {
+		return "", "", fmt.Errorf("GITHUB_REPOSITORY %q has an invalid repository name %q", s, repo)
+	}
+	return owner, repo, nil
+}
+
+// parseNumber parses the PR or issue number in the named variable, which must be a positive
+// number GitHub could have assigned.
+func parseNumber(name, s string) (int, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n <= 0 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("%s %q is not a positive number", name, s)
+	}
+	return int(n), nil
+}
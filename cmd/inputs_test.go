@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseInputs(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		repository  string
+		prNumber    string
+		issueNumber string
+		eventType   string
+		eventName   string
+		want        inputs
+		wantErr     string
+	}{
+		{name: "valid", token: "t", repository: "octo-org/my.repo_1", prNumber: "42", want: inputs{Token: "t", Owner: "octo-org", Repo: "my.repo_1", EventType: eventTypePullRequest, PRNumber: 42}},
+		{name: "managed user owner", token: "t", repository: "octocat_acme/r", prNumber: "1", want: inputs{Token: "t", Owner: "octocat_acme", Repo: "r", EventType: eventTypePullRequest, PRNumber: 1}},
+		{name: "no PR number", token: "t", repository: "o/r", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypePullRequest}},
+		{name: "surrounding whitespace and slashes", token: "t", repository: " o/r/\n", prNumber: " 7 ", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypePullRequest, PRNumber: 7}},
+		{name: "issues event", token: "t", repository: "o/r", eventName: "issues", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypeIssues}},
+		{name: "event type overrides event name", token: "t", repository: "o/r", eventType: "pull_request", eventName: "issues", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypePullRequest}},
+		{name: "explicit issues", token: "t", repository: "o/r", eventType: "issues", eventName: "workflow_dispatch", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypeIssues}},
+		{name: "issue number", token: "t", repository: "o/r", eventType: "issues", prNumber: "3", issueNumber: "9", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypeIssues, PRNumber: 3, IssueNumber: 9}},
+		{name: "issue number falls back to PR number", token: "t", repository: "o/r", eventType: "issues", prNumber: "3", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypeIssues, PRNumber: 3, IssueNumber: 3}},
+		{name: "issue number ignored for PRs", token: "t", repository: "o/r", prNumber: "3", issueNumber: "9", want: inputs{Token: "t", Owner: "o", Repo: "r", EventType: eventTypePullRequest, PRNumber: 3}},
+		{name: "unknown event type", token: "t", repository: "o/r", eventType: "push", wantErr: `EVENT_TYPE "push" is neither pull_request nor issues`},
+		{name: "missing token", repository: "o/r", wantErr: "GITHUB_TOKEN env not set"},
+		{name: "missing repository", token: "t", wantErr: "GITHUB_REPOSITORY env not set"},
+		{name: "no slash", token: "t", repository: "repo", wantErr: "not in owner/repo format"},
+		{name: "extra slash", token: "t", repository: "github.example.com/o/r", wantErr: "not in owner/repo format"},
+		{name: "path after repo", token: "t", repository: "o/r/pulls", wantErr: "not in owner/repo format"},
+		{name: "empty repo", token: "t", repository: "o//", wantErr: "not in owner/repo format"},
+		{name: "invalid owner", token: "t", repository: "-octo/r", wantErr: `invalid owner "-octo"`},
+		{name: "owner with space", token: "t", repository: "oc to/r", wantErr: `invalid owner "oc to"`},
+		{name: "invalid repo", token: "t", repository: "o/..", wantErr: `invalid repository name ".."`},
+		{name: "non-numeric PR number", token: "t", repository: "o/r", prNumber: "abc", wantErr: `PR_NUMBER "abc" is not a positive number`},
+		{name: "zero PR number", token: "t", repository: "o/r", prNumber: "0", wantErr: "not a positive number"},
+		{name: "negative PR number", token: "t", repository: "o/r", prNumber: "-3", wantErr: "not a positive number"},
+		{name: "huge PR number", token: "t", repository: "o/r", prNumber: "99999999999999999999", wantErr: "not a positive number"},
+		{name: "invalid issue number", token: "t", repository: "o/r", eventType: "issues", issueNumber: "#5", wantErr: `ISSUE_NUMBER "#5" is not a positive number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.token)
+			t.Setenv("GITHUB_REPOSITORY", tt.repository)
+			t.Setenv("PR_NUMBER", tt.prNumber)
+			t.Setenv("ISSUE_NUMBER", tt.issueNumber)
+			t.Setenv("EVENT_TYPE", tt.eventType)
+			t.Setenv("GITHUB_EVENT_NAME", tt.eventName)
+
+			got, err := parseInputs()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseInputs() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseInputs: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseInputs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// issueHandlers registers the handlers that run on the issue of an `issues` event, by the
+// name of the pipeline step they replace. The PR-only handlers, such as size labels and
+// reviewers, have no issue counterpart and are skipped.
+var issueHandlers = map[string]Handler{
+	handlerTitle:    HandlerFunc(handleIssueTitle),
+	handlerAssignee: HandlerFunc(assignIssueAssignee),
+}
+
+// issuePipeline returns the steps of pipeline that apply to issues, in order, with their
+// handlers replaced by the issue ones.
+func issuePipeline(pipeline []step) []step {
+	var steps []step
+	for _, s := range pipeline {
+		if h, ok := issueHandlers[s.name]; ok {
+			steps = append(steps, step{name: s.name, handler: h})
+		}
+	}
+	return steps
+}
+
+// getIssue retrieves the issue by number.
+func getIssue(ctx context.Context, client *Client, owner, repo string, number int) (*github.Issue, error) {
+	var issue *github.Issue
+	err := withRetry(ctx, func() (err error) {
+		issue, _, err = client.Issues.Get(ctx, owner, repo, number)
+		return err
+	})
+	return issue, err
+}
+
+// handleIssueTitle labels the issue by its title, with the PR title labels when the issue is
+// a pull request.
+func handleIssueTitle(ctx context.Context, env *Env) error {
+	cfg := env.Config
+	labelMap := cfg.IssueTitleLabels
+	if env.Issue.IsPullRequest() {
+		labelMap = cfg.TitleLabels
+	}
+	return handleTitleBasedLabel(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.Issue.GetTitle(), env.Issue.Labels, labelMap, nil, cfg)
+}
+
+// assignIssueAssignee assigns the default assignees of an issue that has none, chosen by its
+// author like those of a PR.
+func assignIssueAssignee(ctx context.Context, env *Env) error {
+	if len(env.Issue.Assignees) != 0 {
+		loggerFrom(ctx).Infof("Issue already has assignees")
+		return nil
+	}
+	assignees := defaultAssignees(ctx, env.Client, env.Owner, env.Repo, env.Issue.GetUser().GetLogin(), env.Config)
+	if len(assignees) == 0 {
+		return nil
+	}
+	return addAssignees(ctx, env.Client, env.Owner, env.Repo, env.Number(), assignees, env.Config)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"sort"
+	"strings"
+)
+
+// largePRMarker identifies the comment listing the largest changes of a large PR.
+const largePRMarker = "<!-- auto-assign:large-pr -->"
+
+// handleLargePRWarning comments on PRs changing at least LargePRThreshold lines with the
+// LargePRTopFiles files changing the most lines, so the author can consider splitting the
+// PR. Files matching SizeIgnorePaths are left out. The comment is updated in place on re-runs.
+func handleLargePRWarning(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	cfg := env.Config
+	if !cfg.WarnLargePR {
+		return nil
+	}
+	lines := sizeScore(files, cfg.SizeIgnorePaths, 0)
+	if lines < cfg.LargePRThreshold {
+		return nil
+	}
+
+	body := largePRComment(lines, cfg.LargePRThreshold, largestFiles(files, cfg.SizeIgnorePaths, cfg.LargePRTopFiles))
+	if cfg.DryRun {
+		loggerFrom(ctx).Infof("%s would post large PR comment", dryRunPrefix)
+		return nil
+	}
+	if err := upsertComment(ctx, env.Client, env.Owner, env.Repo, env.Number(), largePRMarker, body); err != nil {
+		return fmt.Errorf("post large PR comment: %w", err)
+	}
+	loggerFrom(ctx).Infof("Posted large PR comment for %d changed lines", lines)
+	return nil
+}
+
+// largestFiles returns up to n files changing the most lines, largest first, leaving out
+// files matching ignore. Files changing as many lines are ordered by name.
+func largestFiles(files []*github.CommitFile, ignore []string, n int) []*github.CommitFile {
+	var ranked []*github.CommitFile
+	for _, file := range files {
+		if !matchAnyGlob(ignore, file.GetFilename()) {
+			ranked = append(ranked, file)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		li, lj := changedLines(ranked[i]), changedLines(ranked[j])
+		if li != lj {
+			return li > lj
+		}
+		return ranked[i].GetFilename() < ranked[j].GetFilename()
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// changedLines returns the number of lines a file adds and deletes.
+func changedLines(file *github.CommitFile) int {
+	return file.GetAdditions() + file.GetDeletions()
+}
+
+// largePRComment returns the body of the large PR comment.
+func largePRComment(lines, threshold int, largest []*github.CommitFile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "This PR changes %d lines, at or above the large PR threshold of %d lines. Consider splitting it into smaller PRs.", lines, threshold)
+	if len(largest) > 0 {
+		b.WriteString("\n\nThe files changing the most lines are:\n")
+		for _, file := range largest {
+			fmt.Fprintf(&b, "\n- `%s`: +%d −%d", file.GetFilename(), file.GetAdditions(), file.GetDeletions())
+		}
+	}
+	return b.String()
+}
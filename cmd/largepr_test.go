@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLargestFiles(t *testing.T) {
+	files := []*github.CommitFile{
+		changedFile("small.go", 10),
+		changedFile("go.sum", 900),
+		changedFile("b.go", 300),
+		changedFile("big.go", 400),
+		changedFile("a.go", 300),
+	}
+	tests := []struct {
+		name   string
+		ignore []string
+		n      int
+		want   []string
+	}{
+		{name: "top files", n: 3, want: []string{"go.sum", "big.go", "a.go"}},
+		{name: "ignored files left out", ignore: []string{"**/*.sum"}, n: 2, want: []string{"big.go", "a.go"}},
+		{name: "fewer files than n", n: 10, want: []string{"go.sum", "big.go", "a.go", "b.go", "small.go"}},
+		{name: "none", n: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, file := range largestFiles(files, tt.ignore, tt.n) {
+				got = append(got, file.GetFilename())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("largestFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleLargePRWarning(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.WarnLargePR = true
+	cfg.LargePRThreshold = 500
+	cfg.LargePRTopFiles = 1
+	ctx := context.Background()
+	client := newFakeClient()
+	env := testEnv(client.Client, cfg, nil)
+
+	if err := handleLargePRWarning(ctx, env, []*github.CommitFile{changedFile("a.go", 499)}); err != nil {
+		t.Fatalf("handleLargePRWarning: %v", err)
+	}
+	if len(client.issues.comments) != 0 {
+		t.Fatalf("commented on a PR below the threshold: %v", client.issues.comments)
+	}
+
+	files := []*github.CommitFile{changedFile("a.go", 200), changedFile("b.go", 300)}
+	for run := 0; run < 2; run++ {
+		if err := handleLargePRWarning(ctx, env, files); err != nil {
+			t.Fatalf("handleLargePRWarning: %v", err)
+		}
+	}
+	if len(client.issues.comments) != 1 {
+		t.Fatalf("got %d comments, want a single comment updated on re-runs", len(client.issues.comments))
+	}
+	body := client.issues.comments[0].GetBody()
+	if !strings.HasPrefix(body, largePRMarker) || !strings.Contains(body, "changes 500 lines") || !strings.Contains(body, "`b.go`") || strings.Contains(body, "`a.go`") {
+		t.Errorf("comment = %q, want the line count and only the largest file", body)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Log formats accepted by LOG_FORMAT.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Logger writes the action's log lines. In the default text format a line is its message,
+// as the standard log package prints it; in the JSON format it is a single-line object with
+// the level, the message, and the fields attached with With, such as the PR and handler.
+type Logger struct {
+	slog *slog.Logger
+}
+
+// newLogger returns a logger writing to w in the given LOG_FORMAT. Unknown formats fall back
+// to text.
+func newLogger(format string, w io.Writer) Logger {
+	if format == logFormatJSON {
+		return Logger{slog.New(slog.NewJSONHandler(w, nil))}
+	}
+	return Logger{slog.New(&textHandler{w: w, mu: &sync.Mutex{}})}
+}
+
+// defaultLogger returns the logger of code running without a context, which writes through
+// the default slog logger.
+func defaultLogger() Logger {
+	return Logger{slog.Default()}
+}
+
+// loggerKey is the context key of the logger.
+type loggerKey struct{}
+
+// contextWithLogger returns a context whose log lines are written by l.
+func contextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// loggerFrom returns the logger carried by ctx, or the default logger.
+func loggerFrom(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+	return defaultLogger()
+}
+
+// With returns a logger adding the given key-value pairs to every JSON line.
+func (l Logger) With(args ...any) Logger {
+	return Logger{l.slog.With(args...)}
+}
+
+// Infof logs a formatted message at the info level.
+func (l Logger) Infof(format string, args ...any) {
+	l.slog.Info(fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted message about a failure the run recovers from.
+func (l Logger) Warnf(format string, args ...any) {
+	l.slog.Warn(fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted message about a failed handler.
+func (l Logger) Errorf(format string, args ...any) {
+	l.slog.Error(fmt.Sprintf(format, args...))
+}
+
+// Fatalf logs a formatted message at the error level and exits with status 1.
+func (l Logger) Fatalf(format string, args ...any) {
+	l.Errorf(format, args...)
+	os.Exit(1)
+}
+
+// textHandler writes log records in the standard log package format, leaving out levels
+// and fields so that plain output stays as readable as before LOG_FORMAT existed.
+type textHandler struct {
+	w  io.Writer
+	mu *sync.Mutex
+}
+
+func (h *textHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *textHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintf(h.w, "%s %s\n", r.Time.Format("2006/01/02 15:04:05"), r.Message)
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	return h
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLogger(logFormatText, &buf).With("pr", 1)
+	l.Infof("Added %s label: %s", "D-n", "D-3")
+	l.Warnf("Failed to list reviews: %v", "boom")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{"Added D-n label: D-3", "Failed to list reviews: boom"}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(want), buf.String())
+	}
+	timestamp := regexp.MustCompile(`^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d `)
+	for i, line := range lines {
+		if !timestamp.MatchString(line) || timestamp.ReplaceAllString(line, "") != want[i] {
+			t.Errorf("line %d = %q, want a timestamp and %q", i, line, want[i])
+		}
+	}
+}
+
+func TestJSONLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := contextWithLogger(context.Background(), newLogger(logFormatJSON, &buf))
+	cfg := testConfig(t)
+	client := newFakeClient()
+	env := testEnv(client.Client, cfg, nil)
+	pipeline := []step{{name: handlerSize, handler: HandlerFunc(func(ctx context.Context, env *Env) error {
+		return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{"D-3"}, "D-n", env.Config)
+	})}}
+
+	if err := runPipeline(ctx, env, pipeline, nil); err != nil {
+		t.Fatalf("runPipeline: %v", err)
+	}
+	var line struct {
+		Level   string
+		Msg     string
+		PR      int
+		Handler string
+		Labels  []string
+	}
+	if err := json.Unmarshal(bytes.SplitN(buf.Bytes(), []byte("\n"), 2)[0], &line); err != nil {
+		t.Fatalf("log line is not JSON: %v: %q", err, buf.String())
+	}
+	if line.Level != "INFO" || line.Msg != "Added D-n label: D-3" || line.PR != 1 || line.Handler != handlerSize || len(line.Labels) != 1 || line.Labels[0] != "D-3" {
+		t.Errorf("log line = %+v, want the label added by the size handler on PR #1", line)
+	}
+}
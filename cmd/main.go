@@ -2,217 +2,448 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/go-github/v45/github"
-	"golang.org/x/oauth2"
-	"log"
-	"math/rand"
+	"log/slog"
 	"os"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
 )
 
 func main() {
-	ctx := context.Background()
+	// Log lines without a PR context, such as those of config loading, go through the default
+	// logger as well.
+	logger := newLogger(envString("LOG_FORMAT", logFormatText), os.Stderr)
+	slog.SetDefault(logger.slog)
+	ctx := contextWithLogger(context.Background(), logger)
 
-	// Retrieve environment variables.
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		log.Fatal("GITHUB_TOKEN env not set")
+	in, err := parseInputs()
+	if err != nil {
+		logger.Fatalf("Invalid inputs: %v", err)
 	}
-	repoFull := os.Getenv("GITHUB_REPOSITORY")
-	if repoFull == "" {
-		log.Fatal("GITHUB_REPOSITORY env not set")
+	owner, repo := in.Owner, in.Repo
+
+	cfg, err := loadConfig(envString("CONFIG_PATH", defaultConfigPath))
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
 	}
-	parts := strings.Split(repoFull, "/")
-	if len(parts) != 2 {
-		log.Fatal("GITHUB_REPOSITORY format invalid")
+	if cfg.DryRun {
+		logger.Infof("%s no labels, assignees, reviewers, or comments will be changed", dryRunPrefix)
+	}
+	pipeline, err := buildPipeline(cfg.Pipeline)
+	if err != nil {
+		logger.Fatalf("Invalid PIPELINE: %v", err)
 	}
-	owner, repo := parts[0], parts[1]
 
-	prNumberStr := os.Getenv("PR_NUMBER")
-	if prNumberStr == "" {
-		log.Fatal("PR_NUMBER env not set")
+	// Every API call is made with ctx, so the timeout cancels calls that hang.
+	if cfg.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.RunTimeout)
+		defer cancel()
 	}
-	prNumber, err := strconv.Atoi(prNumberStr)
+
+	// Create GitHub client. GitHub Enterprise Server runners set GITHUB_API_URL to their
+	// instance's API; GHE_BASE_URL overrides it.
+	ctx = contextWithRetryAttempts(ctx, cfg.RetryAttempts)
+	client, err := newGitHubClient(ctx, in.Token, envString("GHE_BASE_URL", os.Getenv("GITHUB_API_URL")))
 	if err != nil {
-		log.Fatalf("Invalid PR_NUMBER: %v", err)
+		logger.Fatalf("Failed to create GitHub client: %v", err)
 	}
 
-	// Create GitHub client.
-	client := newGitHubClient(ctx, token)
+	if cfg.Reconcile {
+		if err := reconcileOpenPullRequests(ctx, client, owner, repo, cfg, pipeline); err != nil {
+			logger.Fatalf("Reconcile failed: %v", err)
+		}
+		return
+	}
+
+	if in.EventType == eventTypeIssues {
+		number := in.IssueNumber
+		if number == 0 {
+			logger.Fatalf("ISSUE_NUMBER env not set")
+		}
+		issue, err := getIssue(ctx, client, owner, repo, number)
+		if err != nil {
+			logger.Fatalf("Failed to get issue #%d: %v", number, err)
+		}
+		if reason := skipLabelReason(issue.Labels, cfg); reason != "" {
+			logger.Infof("Skipping issue #%d because %s", number, reason)
+			writeSkippedStepSummary(number, reason, cfg)
+			return
+		}
+		env := &Env{Client: client, Owner: owner, Repo: repo, Issue: issue, Config: cfg}
+		if err := runPipeline(ctx, env, issuePipeline(pipeline), nil); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	prNumber := in.PRNumber
+	if prNumber == 0 {
+		logger.Fatalf("PR_NUMBER env not set")
+	}
 
 	// Retrieve the pull request details.
 	pr, err := getPullRequest(ctx, client, owner, repo, prNumber)
 	if err != nil {
-		log.Fatalf("Failed to get PR #%d: %v", prNumber, err)
+		logger.Fatalf("Failed to get PR #%d: %v", prNumber, err)
 	}
 
-	// Process each feature.
-	handleTitleBasedLabel(ctx, client, owner, repo, prNumber, pr)
-	handleDayLabel(ctx, client, owner, repo, prNumber, pr)
-	assignDefaultAssignee(ctx, client, owner, repo, prNumber, pr)
-	assignDefaultReviewers(ctx, client, owner, repo, prNumber, pr)
-}
+	if reason := skipReason(pr, cfg); reason != "" {
+		logger.Infof("Skipping PR #%d because %s", prNumber, reason)
+		writeSkippedStepSummary(prNumber, reason, cfg)
+		return
+	}
 
-// newGitHubClient creates a GitHub client using the provided token.
-func newGitHubClient(ctx context.Context, token string) *github.Client {
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	return github.NewClient(oauth2.NewClient(ctx, ts))
+	// Pushes change the diff, so an existing size label may be stale.
+	action := eventAction()
+	if action == "synchronize" {
+		cfg.SizeLabelUpdate = true
+	}
+	env := &Env{Client: client, Owner: owner, Repo: repo, PR: pr, Config: cfg}
+	// Handlers that had nothing to do return nil, so any error is a real failure, such as an
+	// API error, and fails the job once every handler has run.
+	if err := runPipeline(ctx, env, pipeline, handlersForEvent(action, cfg)); err != nil {
+		os.Exit(1)
+	}
 }
 
 // getPullRequest retrieves the pull request by number.
-func getPullRequest(ctx context.Context, client *github.Client, owner, repo string, prNumber int) (*github.PullRequest, error) {
-	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+func getPullRequest(ctx context.Context, client *Client, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	var pr *github.PullRequest
+	err := withRetry(ctx, func() (err error) {
+		pr, _, err = client.PullRequests.Get(ctx, owner, repo, prNumber)
+		return err
+	})
 	return pr, err
 }
 
-// handleTitleBasedLabel adds labels based on the PR title keywords.
-func handleTitleBasedLabel(ctx context.Context, client *github.Client, owner, repo string, prNumber int, pr *github.PullRequest) {
-	title := pr.GetTitle()
-	if !strings.Contains(strings.ToLower(title), ":") {
-		log.Fatalf("PR title does not contain a colon: %s", title)
+// skipReason returns why no handler may run for the PR, or "" when the pipeline runs: the
+// PR carries the SkipLabel opt-out label, or it is a draft under SkipDrafts.
+func skipReason(pr *github.PullRequest, cfg *Config) string {
+	if reason := skipLabelReason(pr.Labels, cfg); reason != "" {
+		return reason
 	}
+	if cfg.SkipDrafts && pr.GetDraft() {
+		return "it is a draft and SKIP_DRAFTS is set"
+	}
+	return ""
+}
 
-	// Split the title into a prefix and description.
-	parts := strings.SplitN(title, ":", 2)
-	prefix := strings.ToLower(strings.TrimSpace(parts[0]))
-
-	// if prefix has any brackets, remove them
-	re := regexp.MustCompile(`[\(\[\{<].*$`)
-	prefix = re.ReplaceAllString(prefix, "")
-
-	labelMap := map[string]string{
-		"feat":     "enhancement",
-		"fix":      "bug",
-		"docs":     "documentation",
-		"style":    "style",
-		"refactor": "refactor",
-		"perf":     "performance",
-		"test":     "test",
-		"chore":    "chore",
+// skipLabelReason returns why no handler may run for a PR or issue with labels, or "" when
+// none of them is the SkipLabel opt-out label.
+func skipLabelReason(labels []*github.Label, cfg *Config) string {
+	if cfg.SkipLabel != "" {
+		for _, l := range labels {
+			if strings.EqualFold(l.GetName(), cfg.SkipLabel) {
+				return fmt.Sprintf("it has the %s label, which disables auto-assignment", l.GetName())
+			}
+		}
 	}
-	label, ok := labelMap[prefix]
-	if !ok {
-		log.Fatalf("No matching label for prefix: %s", prefix)
+	return ""
+}
+
+// handlePullRequestTitle labels the PR by its title and, for reverts, optionally notifies
+// the author of the reverted change.
+func handlePullRequestTitle(ctx context.Context, env *Env) error {
+	cfg := env.Config
+	title := effectiveTitle(ctx, env.Client, env.Owner, env.Repo, env.PR, cfg)
+	err := handleTitleBasedLabel(ctx, env.Client, env.Owner, env.Repo, env.Number(), title, env.PR.Labels, cfg.TitleLabels, otherHandlerLabels(ctx, env), cfg)
+	if cfg.RevertNotifyAuthor && isRevertTitle(title) {
+		notifyRevertedAuthor(ctx, env.Client, env.Owner, env.Repo, env.PR, cfg)
 	}
+	return err
+}
 
-	for _, l := range pr.Labels {
-		if l.GetName() == label {
-			log.Printf("PR already has label: %s", label)
-			return
+// otherHandlerLabels returns the labels the other label handlers pick for the PR, and those
+// they apply by PR state, such as needs-rebase. The title handler never removes them as
+// stale, even when they are title labels as well, such as "enhancement" for both `feat:`
+// and `feature/*` branches: the handler owning a label doesn't add it again when the PR
+// had it at the start of the run.
+func otherHandlerLabels(ctx context.Context, env *Env) []string {
+	cfg, pr := env.Config, env.PR
+	labels := branchLabels(pr.GetHead().GetRef(), cfg)
+	if cfg.TemplateQuestion != "" {
+		if answer, ok := templateAnswer(pr.GetBody(), cfg.TemplateQuestion); ok {
+			labels = append(labels, answerValue(cfg.TemplateAnswerLabels, answer))
+		} else {
+			labels = append(labels, cfg.TemplateIncompleteLabel)
 		}
 	}
+	labels = append(labels, cfg.NeedsRebaseLabel, cfg.NeedsWorkLabel)
 
-	_, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, prNumber, []string{label})
+	files, err := env.changedFiles(ctx)
 	if err != nil {
-		log.Printf("Failed to add title-based labels: %v", err)
-	} else {
-		log.Printf("Added title-based labels: %v", label)
+		// Without the files, every label the files handlers may pick is kept.
+		for _, ct := range cfg.ChangeTypes {
+			labels = append(labels, ct.Label)
+		}
+		for _, b := range append(append([]Bucket(nil), cfg.SizeBuckets...), cfg.EffortBuckets...) {
+			labels = append(labels, b.Label)
+		}
+		for _, name := range bumpNames {
+			labels = append(labels, cfg.SemverLabelPrefix+name)
+		}
+		return append(labels, cfg.NeedsDocsLabel)
+	}
+	labels = append(labels,
+		classifyChangeType(files, cfg),
+		selectSizeLabel(sizeScore(files, cfg.SizeIgnorePaths, cfg.SizeFileWeight), cfg.SizeBuckets),
+		selectSizeLabel(sizeScore(files, cfg.SizeIgnorePaths, cfg.EffortFileWeight), cfg.EffortBuckets),
+	)
+	if len(cfg.DocsSourcePaths) != 0 && needsDocs(files, cfg.DocsSourcePaths, cfg.DocsPaths) {
+		labels = append(labels, cfg.NeedsDocsLabel)
 	}
+	if cfg.SemverLabels {
+		if bump := detectBump(ctx, files, cfg); bump != bumpNone {
+			labels = append(labels, cfg.SemverLabelPrefix+bumpNames[bump])
+		}
+	}
+	return labels
 }
 
-// handleDayLabel calculates code change size and adds a D-n label accordingly.
-func handleDayLabel(ctx context.Context, client *github.Client, owner, repo string, prNumber int, pr *github.PullRequest) {
-	files, _, err := client.PullRequests.ListFiles(ctx, owner, repo, prNumber, nil)
-	if err != nil {
-		log.Printf("Failed to list changed files: %v", err)
-		return
-	}
+// errInvalidTitle marks title failures that fail the run under TITLE_STRICT. Without it,
+// an invalid title is only logged and doesn't fail the run.
+var errInvalidTitle = errors.New("invalid title")
 
-	totalChanges := 0
-	for _, file := range files {
-		totalChanges += file.GetAdditions() + file.GetDeletions()
-	}
+// handleTitleBasedLabel adds labels based on the title keywords of a PR or issue.
+// labelMap maps title prefixes to labels for the kind of object being processed; every
+// mapped label the PR or issue doesn't have yet is added, and the labels of other prefixes,
+// left over from before a title edit, are removed. Labels in keep, which other handlers
+// apply, are never removed.
+func handleTitleBasedLabel(ctx context.Context, client *Client, owner, repo string, number int, title string, labels []*github.Label, labelMap map[string][]string, keep []string, cfg *Config) error {
+	prefix, literal := literalPrefix(title, labelMap)
+	if isRevertTitle(title) {
+		// GitHub's default revert title (`Revert "feat: ..."`) has no prefix of its own.
+		prefix = "revert"
+	} else if !literal {
+		if err := validateTitle(title, cfg); err != nil {
+			if cfg.TitleStrict {
+				return fmt.Errorf("%w: %v", errInvalidTitle, err)
+			}
+			loggerFrom(ctx).Warnf("Invalid title: %v", err)
+			if !cfg.RequireTitleDescription {
+				return addTriageLabel(ctx, client, owner, repo, number, labels, cfg)
+			}
+			if err := addTitleLabel(ctx, client, owner, repo, number, labels, cfg.BadTitleLabel, "bad-title", cfg); err != nil {
+				return err
+			}
+			if !strings.Contains(title, ":") {
+				return nil
+			}
+		} else if cfg.RequireTitleDescription {
+			removeManagedLabels(ctx, client, owner, repo, number, labels, []string{cfg.BadTitleLabel}, cfg)
+		}
 
-	var dayLabel string
-	if totalChanges < 200 {
-		dayLabel = "D-3"
-	} else if totalChanges < 500 {
-		dayLabel = "D-5"
-	} else {
-		dayLabel = "D-7"
+		prefix, _, _ = extractPrefix(title)
+		prefix = resolveAlias(prefix, labelMap, cfg.TitleAliases)
 	}
 
-	// Only add a D-n label if one doesn't already exist.
-	for _, lab := range pr.Labels {
-		if strings.HasPrefix(lab.GetName(), "D-") {
-			log.Printf("PR already has a D-n label: %s", lab.GetName())
-			return
+	mapped, ok := labelMap[prefix]
+	if !ok {
+		if cfg.TitleStrict {
+			return fmt.Errorf("%w: no matching label for prefix: %s", errInvalidTitle, prefix)
 		}
+		loggerFrom(ctx).Infof("No matching label for prefix: %s", prefix)
+		return addTriageLabel(ctx, client, owner, repo, number, labels, cfg)
 	}
 
-	_, _, err = client.Issues.AddLabelsToIssue(ctx, owner, repo, prNumber, []string{dayLabel})
-	if err != nil {
-		log.Printf("Failed to add D-n label: %v", err)
-	} else {
-		log.Printf("Added Day label: %s", dayLabel)
+	removeManagedLabels(ctx, client, owner, repo, number, labels, staleTitleLabels(labelMap, append(keep, mapped...)), cfg)
+
+	existing := map[string]bool{}
+	for _, l := range labels {
+		existing[l.GetName()] = true
 	}
+	var add []string
+	for _, label := range mapped {
+		if existing[label] {
+			loggerFrom(ctx).Infof("Already has label: %s", label)
+			continue
+		}
+		add = append(add, label)
+	}
+	if len(add) == 0 {
+		return nil
+	}
+	return addLabels(ctx, client, owner, repo, number, add, "title-based", cfg)
 }
 
-// assignDefaultAssignee sets the PR author as the default assignee if none exists.
-func assignDefaultAssignee(ctx context.Context, client *github.Client, owner, repo string, prNumber int, pr *github.PullRequest) {
-	if len(pr.Assignees) != 0 {
-		log.Printf("PR already has assignees")
-		return
+// staleTitleLabels returns the labels of labelMap other than keep, sorted.
+func staleTitleLabels(labelMap map[string][]string, keep []string) []string {
+	kept := map[string]bool{}
+	for _, l := range keep {
+		kept[l] = true
 	}
-	author := pr.GetUser().GetLogin()
-	_, _, err := client.Issues.AddAssignees(ctx, owner, repo, prNumber, []string{author})
-	if err != nil {
-		log.Printf("Failed to add default assignee: %v", err)
-	} else {
-		log.Printf("Default assignee (%s) added", author)
+	var stale []string
+	for _, mapped := range labelMap {
+		for _, l := range mapped {
+			if !kept[l] {
+				kept[l] = true
+				stale = append(stale, l)
+			}
+		}
 	}
+	sort.Strings(stale)
+	return stale
 }
 
-// assignDefaultReviewers requests default reviewers based on repository contributors.
-func assignDefaultReviewers(ctx context.Context, client *github.Client, owner, repo string, prNumber int, pr *github.PullRequest) {
-	if len(pr.RequestedReviewers) != 0 {
-		log.Printf("PR already has reviewers")
-		return
+// plainPrefixPattern matches conventional prefixes such as "feat", which are parsed from
+// before the colon. Other label map keys, such as Gitmoji codes, match the title literally.
+var plainPrefixPattern = regexp.MustCompile(`^[\w-]+$`)
+
+// literalPrefix returns the longest non-conventional label map key the title starts with.
+func literalPrefix(title string, labelMap map[string][]string) (string, bool) {
+	var prefix string
+	lower := strings.ToLower(title)
+	for k := range labelMap {
+		if !plainPrefixPattern.MatchString(k) && strings.HasPrefix(lower, k) && len(k) > len(prefix) {
+			prefix = k
+		}
+	}
+	return prefix, prefix != ""
+}
+
+// addTriageLabel applies the configured triage label to a title that maps to no label.
+func addTriageLabel(ctx context.Context, client *Client, owner, repo string, number int, labels []*github.Label, cfg *Config) error {
+	if cfg.TriageLabel == "" {
+		return nil
 	}
+	return addTitleLabel(ctx, client, owner, repo, number, labels, cfg.TriageLabel, "triage", cfg)
+}
+
+// maxChangedFilePages caps the pages of changed files listed for a PR. GitHub lists at most
+// 3000 files, which is 30 pages of 100.
+const maxChangedFilePages = 30
 
-	opts := &github.ListCollaboratorsOptions{ListOptions: github.ListOptions{PerPage: 100}}
-	var collaborators []string
-	for {
-		collaborator, resp, err := client.Repositories.ListCollaborators(ctx, owner, repo, opts)
+// listChangedFiles returns the files changed by the pull request. Files beyond
+// maxChangedFilePages pages are left out with a warning.
+func listChangedFiles(ctx context.Context, client *Client, owner, repo string, prNumber int) ([]*github.CommitFile, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var files []*github.CommitFile
+	for page := 1; ; page++ {
+		var batch []*github.CommitFile
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			batch, resp, err = client.PullRequests.ListFiles(ctx, owner, repo, prNumber, opts)
+			return err
+		})
 		if err != nil {
-			log.Printf("Failed to list collaborators: %v", err)
-			break
-		}
-		for _, c := range collaborator {
-			if c.GetLogin() == pr.GetUser().GetLogin() {
-				continue
-			}
-			collaborators = append(collaborators, c.GetLogin())
+			return nil, err
 		}
+		files = append(files, batch...)
 		if resp.NextPage == 0 {
-			break
+			return files, nil
+		}
+		if page == maxChangedFilePages {
+			loggerFrom(ctx).Warnf("PR #%d changes more than %d files; the remaining files are left out", prNumber, len(files))
+			return files, nil
 		}
 		opts.Page = resp.NextPage
 	}
-	if len(collaborators) == 0 {
-		log.Printf("No collaborators found")
-		return
+}
+
+// handleDayLabel calculates code change size and adds a D-n label accordingly. The size is
+// the number of changed lines plus SizeFileWeight per changed file.
+// With SizeLabelUpdate, an existing D-n label that no longer matches is replaced.
+func handleDayLabel(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	cfg := env.Config
+	score := sizeScore(files, cfg.SizeIgnorePaths, cfg.SizeFileWeight)
+	dayLabel := selectSizeLabel(score, cfg.SizeBuckets)
+	if dayLabel == "" {
+		loggerFrom(ctx).Infof("No size bucket for size %d", score)
+		return nil
 	}
 
-	var reviewers []string
-	if len(collaborators) > 10 {
-		rand.Shuffle(len(collaborators), func(i, j int) {
-			collaborators[i], collaborators[j] = collaborators[j], collaborators[i]
-		})
-		reviewers = collaborators[:10]
-	} else {
-		reviewers = collaborators
+	sizeLabels := map[string]bool{}
+	for _, b := range cfg.SizeBuckets {
+		sizeLabels[b.Label] = true
 	}
 
-	reviewersRequest := github.ReviewersRequest{
-		Reviewers: reviewers,
+	// Only add a D-n label if one doesn't already exist, unless it is being updated.
+	var stale []string
+	for _, lab := range env.PR.Labels {
+		if !sizeLabels[lab.GetName()] {
+			continue
+		}
+		if lab.GetName() == dayLabel || !cfg.SizeLabelUpdate {
+			loggerFrom(ctx).Infof("PR already has a D-n label: %s", lab.GetName())
+			return nil
+		}
+		stale = append(stale, lab.GetName())
 	}
-	_, _, err := client.PullRequests.RequestReviewers(ctx, owner, repo, prNumber, reviewersRequest)
-	if err != nil {
-		log.Printf("Failed to add default reviewers: %v", err)
-	} else {
-		log.Printf("Default reviewers added: %v", reviewers)
+	removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, stale, cfg)
+
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{dayLabel}, "D-n", cfg)
+}
+
+// Assignee strategies accepted by ASSIGNEE_STRATEGY.
+const (
+	assigneeStrategyAuthor     = "author"
+	assigneeStrategyConfigured = "configured"
+)
+
+// assignDefaultAssignee sets the default assignees of a PR that has none: the PR author, or
+// DefaultAssignees under the configured assignee strategy. When the PR closes a high-priority
+// issue, the priority assignees are used instead. Authors without write access, such as fork
+// contributors, are replaced by FallbackAssignee.
+func assignDefaultAssignee(ctx context.Context, env *Env) error {
+	if len(env.PR.Assignees) != 0 {
+		loggerFrom(ctx).Infof("PR already has assignees")
+		summaryFrom(ctx).addSkipped("assignees: the PR already has assignees")
+		return nil
+	}
+	assignees, _ := env.priorityRouting(ctx)
+	if len(assignees) == 0 {
+		assignees = defaultAssignees(ctx, env.Client, env.Owner, env.Repo, env.PR.GetUser().GetLogin(), env.Config)
+		if len(assignees) == 0 {
+			return nil
+		}
+	}
+	return addAssignees(ctx, env.Client, env.Owner, env.Repo, env.Number(), assignees, env.Config)
+}
+
+// defaultAssignees returns the assignees of an unassigned PR or issue by its author under
+// the assignee strategy, or nil when the author cannot be assigned and FallbackAssignee is
+// not set.
+func defaultAssignees(ctx context.Context, client *Client, owner, repo, author string, cfg *Config) []string {
+	if assigneeStrategy(ctx, cfg) == assigneeStrategyConfigured {
+		return cfg.DefaultAssignees
+	}
+	switch {
+	case canBeAssigned(ctx, client, owner, repo, author):
+		return []string{author}
+	case cfg.FallbackAssignee != "":
+		loggerFrom(ctx).Infof("Author %s cannot be assigned, using the fallback assignee", author)
+		return []string{cfg.FallbackAssignee}
+	default:
+		loggerFrom(ctx).Infof("Author %s cannot be assigned and FALLBACK_ASSIGNEE is not set", author)
+		summaryFrom(ctx).addSkipped("assignees: the author %s cannot be assigned and FALLBACK_ASSIGNEE is not set", author)
+		return nil
+	}
+}
+
+// assigneeStrategy returns the configured assignee strategy. Without one, DefaultAssignees
+// are assigned when set, and the author otherwise. The configured strategy falls back to the
+// author while DefaultAssignees is empty.
+func assigneeStrategy(ctx context.Context, cfg *Config) string {
+	switch cfg.AssigneeStrategy {
+	case "":
+		if len(cfg.DefaultAssignees) != 0 {
+			return assigneeStrategyConfigured
+		}
+		return assigneeStrategyAuthor
+	case assigneeStrategyAuthor:
+		return assigneeStrategyAuthor
+	case assigneeStrategyConfigured:
+		if len(cfg.DefaultAssignees) == 0 {
+			loggerFrom(ctx).Infof("ASSIGNEE_STRATEGY is %q but DEFAULT_ASSIGNEES is empty, assigning the author", assigneeStrategyConfigured)
+			return assigneeStrategyAuthor
+		}
+		return assigneeStrategyConfigured
+	default:
+		loggerFrom(ctx).Warnf("Unknown assignee strategy %q, assigning the author", cfg.AssigneeStrategy)
+		return assigneeStrategyAuthor
 	}
 }
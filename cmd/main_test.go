@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"reflect"
+	"testing"
+)
+
+func TestHandleTitleBasedLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		title       string
+		labels      []string
+		configure   func(*Config)
+		wantAdded   []string
+		wantRemoved []string
+		wantErr     error
+	}{
+		{name: "prefix", title: "feat: add export", wantAdded: []string{"enhancement"}},
+		{name: "scoped prefix", title: "fix(api): handle nil", wantAdded: []string{"bug"}},
+		{name: "uppercase prefix", title: "Docs: Fix typo", wantAdded: []string{"documentation"}},
+		{name: "revert", title: `Revert "feat: add export"`, wantAdded: []string{"revert"}},
+		{name: "alias", title: "Feature: add export", wantAdded: []string{"enhancement"}},
+		{name: "alias with separator", title: "Bug-Fix: handle nil", wantAdded: []string{"bug"}},
+		{
+			name:  "direct match wins over alias",
+			title: "hotfix: patch release",
+			configure: func(cfg *Config) {
+				cfg.TitleLabels["hotfix"] = []string{"hotfix"}
+			},
+			wantAdded: []string{"hotfix"},
+		},
+		{
+			name:      "configured alias",
+			title:     "enhancement: add export",
+			configure: func(cfg *Config) { cfg.TitleAliases["enhancement"] = "feat" },
+			wantAdded: []string{"enhancement"},
+		},
+		{name: "already labeled", title: "feat: add export", labels: []string{"enhancement"}},
+		{name: "title edited", title: "fix: handle nil", labels: []string{"enhancement", "wontfix"}, wantAdded: []string{"bug"}, wantRemoved: []string{"enhancement"}},
+		{
+			name:        "stale label shared by the new prefix",
+			title:       "fix: handle nil",
+			labels:      []string{"bug", "enhancement"},
+			configure:   func(cfg *Config) { cfg.TitleLabels["feat"] = []string{"enhancement", "bug"} },
+			wantRemoved: []string{"enhancement"},
+		},
+		{name: "unknown prefix", title: "wip: messing around"},
+		{name: "no colon", title: "WIP messing around"},
+		{
+			name:      "unknown prefix with triage label",
+			title:     "wip: messing around",
+			configure: func(cfg *Config) { cfg.TriageLabel = "needs-triage" },
+			wantAdded: []string{"needs-triage"},
+		},
+		{
+			name:      "strict",
+			title:     "wip: messing around",
+			configure: func(cfg *Config) { cfg.TitleStrict = true },
+			wantErr:   errInvalidTitle,
+		},
+		{
+			name:      "several labels, one present",
+			title:     "fix: handle nil",
+			labels:    []string{"bug"},
+			configure: func(cfg *Config) { cfg.TitleLabels["fix"] = []string{"bug", "needs-review"} },
+			wantAdded: []string{"needs-review"},
+		},
+		{
+			name:      "literal prefix",
+			title:     ":sparkles: add export",
+			configure: func(cfg *Config) { cfg.TitleLabels[":sparkles:"] = []string{"enhancement"} },
+			wantAdded: []string{"enhancement"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			client := newFakeClient()
+			err := handleTitleBasedLabel(context.Background(), client.Client, "o", "r", 1, tt.title, labels(tt.labels...), cfg.TitleLabels, nil, cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(client.issues.added, tt.wantAdded) {
+				t.Errorf("added labels = %v, want %v", client.issues.added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(client.issues.removed, tt.wantRemoved) {
+				t.Errorf("removed labels = %v, want %v", client.issues.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestHandleDayLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []*github.CommitFile
+		labels      []string
+		configure   func(*Config)
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{name: "small", files: []*github.CommitFile{changedFile("a.go", 50)}, wantAdded: []string{"D-3"}},
+		{name: "medium", files: []*github.CommitFile{changedFile("a.go", 150), changedFile("b.go", 150)}, wantAdded: []string{"D-5"}},
+		{name: "large", files: []*github.CommitFile{changedFile("a.go", 500)}, wantAdded: []string{"D-7"}},
+		{name: "already labeled", files: []*github.CommitFile{changedFile("a.go", 500)}, labels: []string{"D-3"}},
+		{
+			name:        "stale label updated",
+			files:       []*github.CommitFile{changedFile("a.go", 500)},
+			labels:      []string{"D-3"},
+			configure:   func(cfg *Config) { cfg.SizeLabelUpdate = true },
+			wantAdded:   []string{"D-7"},
+			wantRemoved: []string{"D-3"},
+		},
+		{
+			name:      "ignored paths",
+			files:     []*github.CommitFile{changedFile("go.sum", 10000), changedFile("a.go", 10)},
+			configure: func(cfg *Config) { cfg.SizeIgnorePaths = []string{"go.sum"} },
+			wantAdded: []string{"D-3"},
+		},
+		{
+			name:      "file weight",
+			files:     []*github.CommitFile{changedFile("a.go", 1), changedFile("b.go", 1), changedFile("c.go", 1)},
+			configure: func(cfg *Config) { cfg.SizeFileWeight = 100 },
+			wantAdded: []string{"D-5"},
+		},
+		{
+			name:      "custom buckets",
+			files:     []*github.CommitFile{changedFile("a.go", 50)},
+			configure: func(cfg *Config) { cfg.SizeBuckets = []Bucket{{MaxChanges: 20, Label: "XS"}, {Label: "L"}} },
+			wantAdded: []string{"L"},
+		},
+		{
+			name:      "no matching bucket",
+			files:     []*github.CommitFile{changedFile("a.go", 50)},
+			configure: func(cfg *Config) { cfg.SizeBuckets = []Bucket{{MaxChanges: 20, Label: "XS"}} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			client := newFakeClient()
+			client.pullRequests.files = tt.files
+			if err := handleDayLabel(context.Background(), testEnv(client.Client, cfg, labels(tt.labels...)), tt.files); err != nil {
+				t.Fatalf("handleDayLabel: %v", err)
+			}
+			if !reflect.DeepEqual(client.issues.added, tt.wantAdded) {
+				t.Errorf("added labels = %v, want %v", client.issues.added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(client.issues.removed, tt.wantRemoved) {
+				t.Errorf("removed labels = %v, want %v", client.issues.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	for _, tt := range []struct{ files, want int }{{0, 0}, {250, 250}, {3000, 3000}, {3500, 3000}} {
+		client := newFakeClient()
+		for i := 0; i < tt.files; i++ {
+			client.pullRequests.files = append(client.pullRequests.files, changedFile(fmt.Sprintf("f%d.go", i), 1))
+		}
+		files, err := listChangedFiles(context.Background(), client.Client, "o", "r", 1)
+		if err != nil {
+			t.Fatalf("listChangedFiles: %v", err)
+		}
+		if len(files) != tt.want {
+			t.Errorf("listChangedFiles of %d files returned %d, want %d", tt.files, len(files), tt.want)
+		}
+	}
+}
+
+func TestAssignDefaultAssignee(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*Config)
+		assignees []*github.User
+		want      []string
+	}{
+		{name: "author", want: []string{"author"}},
+		{
+			name:      "default assignees",
+			configure: func(cfg *Config) { cfg.DefaultAssignees = []string{"alice", "bob"} },
+			want:      []string{"alice", "bob"},
+		},
+		{
+			name: "author strategy",
+			configure: func(cfg *Config) {
+				cfg.DefaultAssignees = []string{"alice"}
+				cfg.AssigneeStrategy = assigneeStrategyAuthor
+			},
+			want: []string{"author"},
+		},
+		{
+			name:      "configured strategy without default assignees",
+			configure: func(cfg *Config) { cfg.AssigneeStrategy = assigneeStrategyConfigured },
+			want:      []string{"author"},
+		},
+		{
+			name:      "already assigned",
+			configure: func(cfg *Config) { cfg.DefaultAssignees = []string{"alice"} },
+			assignees: []*github.User{{Login: github.String("carol")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			client := newFakeClient()
+			client.repositories.permissions["author"] = "write"
+			env := testEnv(client.Client, cfg, nil)
+			env.PR.Assignees = tt.assignees
+
+			if err := assignDefaultAssignee(context.Background(), env); err != nil {
+				t.Fatalf("assignDefaultAssignee: %v", err)
+			}
+			if !reflect.DeepEqual(client.issues.assignees, tt.want) {
+				t.Errorf("assigned %v, want %v", client.issues.assignees, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignIssueAssignee(t *testing.T) {
+	tests := []struct {
+		name      string
+		author    string
+		fallback  string
+		assignees []*github.User
+		want      []string
+	}{
+		{name: "author", author: "author", want: []string{"author"}},
+		{name: "outside author gets fallback", author: "reporter", fallback: "triager", want: []string{"triager"}},
+		{name: "outside author without fallback", author: "reporter"},
+		{name: "already assigned", author: "author", assignees: []*github.User{{Login: github.String("carol")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.FallbackAssignee = tt.fallback
+			client := newFakeClient()
+			client.repositories.permissions["author"] = "write"
+			issue := &github.Issue{Number: github.Int(5), User: &github.User{Login: github.String(tt.author)}, Assignees: tt.assignees}
+			env := &Env{Client: client.Client, Owner: "o", Repo: "r", Issue: issue, Config: cfg}
+
+			if err := assignIssueAssignee(context.Background(), env); err != nil {
+				t.Fatalf("assignIssueAssignee: %v", err)
+			}
+			if !reflect.DeepEqual(client.issues.assignees, tt.want) {
+				t.Errorf("assigned %v, want %v", client.issues.assignees, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipReason(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    []*github.Label
+		draft     bool
+		configure func(*Config)
+		skip      bool
+	}{
+		{name: "regular PR"},
+		{name: "opt-out label", labels: labels("bug", "Skip-Auto-Assign"), skip: true},
+		{name: "custom opt-out label", labels: labels("hands-off"), configure: func(cfg *Config) { cfg.SkipLabel = "hands-off" }, skip: true},
+		{name: "opt-out label disabled", labels: labels("skip-auto-assign"), configure: func(cfg *Config) { cfg.SkipLabel = "" }},
+		{name: "draft", draft: true},
+		{name: "draft with SKIP_DRAFTS", draft: true, configure: func(cfg *Config) { cfg.SkipDrafts = true }, skip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			pr := &github.PullRequest{Labels: tt.labels, Draft: github.Bool(tt.draft)}
+			if got := skipReason(pr, cfg); (got != "") != tt.skip {
+				t.Errorf("skipReason = %q, want skip %v", got, tt.skip)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// managedLabels returns the labels the action owns and may therefore remove or replace.
+// An explicit MANAGED_LABELS list wins; otherwise the set is derived from every label
+// value the action is configured to apply.
+func managedLabels(cfg *Config) map[string]bool {
+	managed := map[string]bool{}
+	if len(cfg.ManagedLabels) != 0 {
+		for _, l := range cfg.ManagedLabels {
+			managed[l] = true
+		}
+		return managed
+	}
+
+	for _, labels := range cfg.TitleLabels {
+		for _, l := range labels {
+			managed[l] = true
+		}
+	}
+	for _, labels := range cfg.IssueTitleLabels {
+		for _, l := range labels {
+			managed[l] = true
+		}
+	}
+	for _, rule := range cfg.BranchLabels {
+		managed[rule.Value] = true
+	}
+	for _, b := range cfg.SizeBuckets {
+		managed[b.Label] = true
+	}
+	for _, b := range cfg.EffortBuckets {
+		managed[b.Label] = true
+	}
+	for _, ct := range cfg.ChangeTypes {
+		managed[ct.Label] = true
+	}
+	for _, level := range bumpNames {
+		managed[cfg.SemverLabelPrefix+level] = true
+	}
+	for _, rule := range cfg.TemplateAnswerLabels {
+		managed[rule.Value] = true
+	}
+	managed[cfg.TemplateIncompleteLabel] = true
+	managed[cfg.BadTitleLabel] = true
+	managed[cfg.NeedsDocsLabel] = true
+	managed[cfg.NeedsWorkLabel] = true
+	managed[cfg.NeedsRebaseLabel] = true
+	return managed
+}
+
+// removeManagedLabels removes the given labels from a PR or issue, skipping any label that
+// isn't present or that the action doesn't manage, so human-applied labels are never touched.
+func removeManagedLabels(ctx context.Context, client *Client, owner, repo string, number int, current []*github.Label, remove []string, cfg *Config) {
+	managed := managedLabels(cfg)
+	present := map[string]bool{}
+	for _, l := range current {
+		present[l.GetName()] = true
+	}
+	for _, label := range remove {
+		if !present[label] {
+			continue
+		}
+		logger := loggerFrom(ctx).With("label", label)
+		if !managed[label] {
+			logger.Infof("Not removing unmanaged label: %s", label)
+			continue
+		}
+		if cfg.DryRun {
+			logger.Infof("%s would remove label: %s", dryRunPrefix, label)
+			continue
+		}
+		err := withRetry(ctx, func() error {
+			_, err := client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, label)
+			return err
+		})
+		if err != nil {
+			logger.Warnf("Failed to remove label %s: %v", label, err)
+		} else {
+			logger.Infof("Removed stale label: %s", label)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestManagedLabels(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.BranchLabels = []keyValue{{Key: "feature/*", Value: "feature-branch"}}
+	managed := managedLabels(cfg)
+	for _, l := range []string{"enhancement", "feature-request", "feature-branch", "D-3", "D-7", "semver:major", "bad-title", "needs-docs"} {
+		if !managed[l] {
+			t.Errorf("managedLabels is missing %q", l)
+		}
+	}
+	if managed["wontfix"] {
+		t.Error("managedLabels includes a label the action never applies")
+	}
+
+	cfg.ManagedLabels = []string{"D-3"}
+	if got, want := managedLabels(cfg), map[string]bool{"D-3": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("managedLabels with MANAGED_LABELS = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveManagedLabels(t *testing.T) {
+	cfg := testConfig(t)
+	client := newFakeClient()
+	current := labels("D-3", "wontfix", "bad-title")
+	removeManagedLabels(context.Background(), client.Client, "o", "r", 1, current, []string{"D-3", "D-5", "wontfix"}, cfg)
+	if want := []string{"D-3"}; !reflect.DeepEqual(client.issues.removed, want) {
+		t.Errorf("removed labels = %v, want %v", client.issues.removed, want)
+	}
+
+	cfg.DryRun = true
+	client = newFakeClient()
+	removeManagedLabels(context.Background(), client.Client, "o", "r", 1, current, []string{"D-3"}, cfg)
+	if len(client.issues.removed) != 0 {
+		t.Errorf("dry run removed labels: %v", client.issues.removed)
+	}
+}
+
+func TestUnmanagedLabelsSurviveRemoval(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.ManagedLabels = []string{"enhancement"}
+	client := newFakeClient()
+	current := labels("bug", "needs-triage")
+
+	// "bug" is stale for a feat: title, but MANAGED_LABELS doesn't list it.
+	if err := handleTitleBasedLabel(context.Background(), client.Client, "o", "r", 1, "feat: add endpoint", current, cfg.TitleLabels, nil, cfg); err != nil {
+		t.Fatalf("handleTitleBasedLabel: %v", err)
+	}
+	removeManagedLabels(context.Background(), client.Client, "o", "r", 1, current, []string{"needs-triage"}, cfg)
+	if len(client.issues.removed) != 0 {
+		t.Errorf("removed unmanaged labels %v, want none", client.issues.removed)
+	}
+	if want := []string{"enhancement"}; !reflect.DeepEqual(client.issues.added, want) {
+		t.Errorf("added %v, want %v", client.issues.added, want)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+)
+
+// assignMilestone sets the milestone mapped to the PR base branch, e.g. "1.4" for PRs
+// targeting release/1.4. PRs that already have a milestone are left alone, as are branches
+// no rule matches. A mapped milestone that doesn't exist is logged and skipped.
+func assignMilestone(ctx context.Context, env *Env) error {
+	cfg := env.Config
+	if len(cfg.BranchMilestones) == 0 {
+		return nil
+	}
+	if env.PR.Milestone != nil {
+		loggerFrom(ctx).Infof("PR already has milestone: %s", env.PR.GetMilestone().GetTitle())
+		return nil
+	}
+
+	base := env.PR.GetBase().GetRef()
+	title, ok := branchMilestone(cfg.BranchMilestones, base)
+	if !ok {
+		return nil
+	}
+	milestone, err := findMilestone(ctx, env.Client, env.Owner, env.Repo, title)
+	if err != nil {
+		return fmt.Errorf("list milestones: %w", err)
+	}
+	if milestone == nil {
+		loggerFrom(ctx).Warnf("Milestone %q for base branch %s does not exist or is closed", title, base)
+		return nil
+	}
+
+	if cfg.DryRun {
+		loggerFrom(ctx).Infof("%s would set milestone: %s", dryRunPrefix, title)
+		summaryFrom(ctx).setMilestone(title)
+		return nil
+	}
+	err = withRetry(ctx, func() error {
+		_, _, err := env.Client.Issues.Edit(ctx, env.Owner, env.Repo, env.Number(), &github.IssueRequest{Milestone: milestone.Number})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("set milestone: %w", err)
+	}
+	loggerFrom(ctx).With("milestone", title).Infof("Set milestone: %s", title)
+	summaryFrom(ctx).setMilestone(title)
+	return nil
+}
+
+// branchMilestone returns the milestone mapped to the base branch. A rule naming the branch
+// exactly wins over glob rules, which are tried in order.
+func branchMilestone(rules []keyValue, branch string) (string, bool) {
+	for _, rule := range rules {
+		if rule.Key == branch {
+			return rule.Value, true
+		}
+	}
+	for _, rule := range rules {
+		if matchGlob(rule.Key, branch) {
+			return rule.Value, true
+		}
+	}
+	return "", false
+}
+
+// findMilestone returns the open milestone with the given title, or nil when there is none.
+func findMilestone(ctx context.Context, client *Client, owner, repo, title string) (*github.Milestone, error) {
+	opts := &github.MilestoneListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		var milestones []*github.Milestone
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			milestones, resp, err = client.Issues.ListMilestones(ctx, owner, repo, opts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range milestones {
+			if m.GetTitle() == title {
+				return m, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"testing"
+)
+
+func TestAssignMilestone(t *testing.T) {
+	rules := []keyValue{{Key: "release/*", Value: "next patch"}, {Key: "release/1.4", Value: "1.4"}}
+	milestones := []*github.Milestone{
+		{Number: github.Int(3), Title: github.String("1.4")},
+		{Number: github.Int(4), Title: github.String("next patch")},
+	}
+	tests := []struct {
+		name      string
+		base      string
+		current   *github.Milestone
+		want      int
+		wantEdits int
+	}{
+		{name: "exact rule wins over glob", base: "release/1.4", want: 3, wantEdits: 1},
+		{name: "glob rule", base: "release/1.5", want: 4, wantEdits: 1},
+		{name: "no rule", base: "main"},
+		{name: "already set", base: "release/1.4", current: &github.Milestone{Title: github.String("1.3")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.BranchMilestones = rules
+			client := newFakeClient()
+			client.issues.milestones = milestones
+			env := testEnv(client.Client, cfg, nil)
+			env.PR.Base = &github.PullRequestBranch{Ref: github.String(tt.base)}
+			env.PR.Milestone = tt.current
+
+			if err := assignMilestone(context.Background(), env); err != nil {
+				t.Fatalf("assignMilestone: %v", err)
+			}
+			if len(client.issues.edits) != tt.wantEdits {
+				t.Fatalf("got %d edits, want %d", len(client.issues.edits), tt.wantEdits)
+			}
+			if tt.wantEdits > 0 && client.issues.edits[0].GetMilestone() != tt.want {
+				t.Errorf("set milestone %d, want %d", client.issues.edits[0].GetMilestone(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignMilestoneMissing(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.BranchMilestones = []keyValue{{Key: "main", Value: "2.0"}}
+	client := newFakeClient()
+	client.issues.milestones = []*github.Milestone{{Number: github.Int(1), Title: github.String("1.9")}}
+	env := testEnv(client.Client, cfg, nil)
+	env.PR.Base = &github.PullRequestBranch{Ref: github.String("main")}
+
+	if err := assignMilestone(context.Background(), env); err != nil {
+		t.Fatalf("assignMilestone: %v", err)
+	}
+	if len(client.issues.edits) != 0 {
+		t.Errorf("got edits %v, want none for a missing milestone", client.issues.edits)
+	}
+}
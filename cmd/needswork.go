@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+)
+
+// handleNeedsWorkLabel adds the needs-work label when the PR has at least the threshold of
+// review comment threads, and removes it once the count drops below.
+func handleNeedsWorkLabel(ctx context.Context, env *Env) error {
+	cfg := env.Config
+	if cfg.NeedsWorkThreshold <= 0 {
+		return nil
+	}
+
+	count, err := countReviewThreads(ctx, env.Client, env.Owner, env.Repo, env.Number())
+	if err != nil {
+		return fmt.Errorf("list review comments: %w", err)
+	}
+	if count < cfg.NeedsWorkThreshold {
+		loggerFrom(ctx).Infof("PR has %d review comment threads, below the needs-work threshold of %d", count, cfg.NeedsWorkThreshold)
+		removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, []string{cfg.NeedsWorkLabel}, cfg)
+		return nil
+	}
+
+	loggerFrom(ctx).Infof("PR has %d review comment threads, at or above the needs-work threshold of %d", count, cfg.NeedsWorkThreshold)
+	for _, l := range env.PR.Labels {
+		if l.GetName() == cfg.NeedsWorkLabel {
+			loggerFrom(ctx).Infof("PR already has label: %s", cfg.NeedsWorkLabel)
+			return nil
+		}
+	}
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{cfg.NeedsWorkLabel}, "needs-work", cfg)
+}
+
+// countReviewThreads counts the review comments that start a thread; replies are not counted.
+func countReviewThreads(ctx context.Context, client *Client, owner, repo string, prNumber int) (int, error) {
+	opts := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	count := 0
+	for {
+		var comments []*github.PullRequestComment
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			comments, resp, err = client.PullRequests.ListComments(ctx, owner, repo, prNumber, opts)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range comments {
+			if c.GetInReplyTo() == 0 {
+				count++
+			}
+		}
+		if resp.NextPage == 0 {
+			return count, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"strings"
+)
+
+// permissionLevel returns a user's permission on the repository: admin, write, read, or none.
+func permissionLevel(ctx context.Context, client *Client, owner, repo, user string) (string, error) {
+	var level *github.RepositoryPermissionLevel
+	err := withRetry(ctx, func() (err error) {
+		level, _, err = client.Repositories.GetPermissionLevel(ctx, owner, repo, user)
+		return err
+	})
+	return level.GetPermission(), err
+}
+
+// canBeAssigned reports whether the user has the write access GitHub requires of assignees.
+// External fork contributors usually don't. When the permission can't be read, the
+// assignment is attempted anyway.
+func canBeAssigned(ctx context.Context, client *Client, owner, repo, user string) bool {
+	level, err := permissionLevel(ctx, client, owner, repo, user)
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to get permission of %s: %v", user, err)
+		return true
+	}
+	return level == "admin" || level == "write"
+}
+
+// reviewableUsers drops users without access to the repository, whom GitHub rejects as
+// reviewers, failing the whole request. Team entries and users whose permission can't be
+// read are kept.
+func reviewableUsers(ctx context.Context, client *Client, owner, repo string, reviewers []string) []string {
+	var reviewable []string
+	for _, r := range reviewers {
+		if !strings.Contains(r, "/") {
+			level, err := permissionLevel(ctx, client, owner, repo, r)
+			if err != nil {
+				loggerFrom(ctx).Warnf("Failed to get permission of %s: %v", r, err)
+			} else if level == "none" {
+				loggerFrom(ctx).Infof("Skipping reviewer %s without access to the repository", r)
+				continue
+			}
+		}
+		reviewable = append(reviewable, r)
+	}
+	return reviewable
+}
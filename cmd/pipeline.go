@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+)
+
+// Env carries the pull request being processed and the dependencies shared by the handlers.
+// On `issues` events, Issue is set instead of PR and only the issue handlers run.
+type Env struct {
+	Client *Client
+	Owner  string
+	Repo   string
+	PR     *github.PullRequest
+	Issue  *github.Issue
+	Config *Config
+
+	priorityResolved  bool
+	priorityAssignees []string
+	priorityReviewers []string
+
+	filesListed bool
+	files       []*github.CommitFile
+	filesErr    error
+}
+
+// Number returns the number of the pull request or issue being processed.
+func (e *Env) Number() int {
+	if e.PR == nil {
+		return e.Issue.GetNumber()
+	}
+	return e.PR.GetNumber()
+}
+
+// noun names the kind of object being processed, for messages posted on it.
+func (e *Env) noun() string {
+	if e.PR == nil {
+		return "issue"
+	}
+	return "pull request"
+}
+
+// priorityRouting returns the assignees and reviewers that replace the defaults when the PR
+// closes a high-priority issue. The linked issues are looked up at most once per PR.
+func (e *Env) priorityRouting(ctx context.Context) (assignees, reviewers []string) {
+	if !e.priorityResolved {
+		e.priorityResolved = true
+		cfg := e.Config
+		if len(cfg.PriorityAssignees) != 0 || len(cfg.PriorityReviewers) != 0 {
+			if isHighPriority(ctx, e.Client, e.Owner, e.Repo, e.PR, cfg.PriorityLabels) {
+				e.priorityAssignees, e.priorityReviewers = cfg.PriorityAssignees, cfg.PriorityReviewers
+			}
+		}
+	}
+	return e.priorityAssignees, e.priorityReviewers
+}
+
+// changedFiles returns the files changed by the PR. They are listed at most once per PR and
+// shared by every handler that needs them.
+func (e *Env) changedFiles(ctx context.Context) ([]*github.CommitFile, error) {
+	if !e.filesListed {
+		e.filesListed = true
+		e.files, e.filesErr = listChangedFiles(ctx, e.Client, e.Owner, e.Repo, e.Number())
+	}
+	return e.files, e.filesErr
+}
+
+// Handler is a single step of the processing pipeline.
+type Handler interface {
+	Handle(ctx context.Context, env *Env) error
+}
+
+// HandlerFunc adapts an ordinary function to the Handler interface.
+type HandlerFunc func(ctx context.Context, env *Env) error
+
+// Handle calls f(ctx, env).
+func (f HandlerFunc) Handle(ctx context.Context, env *Env) error {
+	return f(ctx, env)
+}
+
+// FilesHandlerFunc adapts a function operating on the PR's changed files to the Handler
+// interface, so handlers state in their signature that they need the files.
+type FilesHandlerFunc func(ctx context.Context, env *Env, files []*github.CommitFile) error
+
+// Handle lists the changed files of the PR, once per PR, and calls f(ctx, env, files).
+func (f FilesHandlerFunc) Handle(ctx context.Context, env *Env) error {
+	files, err := env.changedFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("list changed files: %w", err)
+	}
+	return f(ctx, env, files)
+}
+
+// Handler names accepted by PIPELINE, EVENT_HANDLERS, and RECONCILE_HANDLERS.
+const (
+	handlerTitle      = "title"
+	handlerBranch     = "branch"
+	handlerSize       = "size"
+	handlerLargePR    = "large-pr"
+	handlerEffort     = "effort"
+	handlerDocs       = "docs"
+	handlerChangeType = "change-type"
+	handlerSemver     = "semver"
+	handlerTemplate   = "template"
+	handlerNeedsWork  = "needs-work"
+	handlerMilestone  = "milestone"
+	handlerAssignee   = "assignee"
+	handlerReviewers  = "reviewers"
+)
+
+// handlers registers every available handler by name.
+var handlers = map[string]Handler{
+	handlerTitle:      HandlerFunc(handlePullRequestTitle),
+	handlerBranch:     HandlerFunc(handleBranchLabel),
+	handlerSize:       FilesHandlerFunc(handleDayLabel),
+	handlerLargePR:    FilesHandlerFunc(handleLargePRWarning),
+	handlerEffort:     FilesHandlerFunc(handleEffortLabel),
+	handlerDocs:       FilesHandlerFunc(handleNeedsDocsLabel),
+	handlerChangeType: FilesHandlerFunc(handleChangeTypeLabel),
+	handlerSemver:     FilesHandlerFunc(handleSemverLabel),
+	handlerTemplate:   HandlerFunc(handleTemplateLabel),
+	handlerNeedsWork:  HandlerFunc(handleNeedsWorkLabel),
+	handlerMilestone:  HandlerFunc(assignMilestone),
+	handlerAssignee:   HandlerFunc(assignDefaultAssignee),
+	handlerReviewers:  FilesHandlerFunc(assignDefaultReviewers),
+}
+
+// defaultPipeline is the order handlers run in when PIPELINE is not set. Besides the title,
+// size, assignee, and reviewer defaults, it contains the optional handlers, which do nothing
+// until configured.
+var defaultPipeline = []string{
+	handlerTitle,
+	handlerBranch,
+	handlerSize,
+	handlerLargePR,
+	handlerEffort,
+	handlerDocs,
+	handlerChangeType,
+	handlerSemver,
+	handlerTemplate,
+	handlerNeedsWork,
+	handlerMilestone,
+	handlerAssignee,
+	handlerReviewers,
+}
+
+// step is a named handler of a pipeline.
+type step struct {
+	name    string
+	handler Handler
+}
+
+// buildPipeline resolves handler names into pipeline steps, keeping their order.
+func buildPipeline(names []string) ([]step, error) {
+	var pipeline []step
+	seen := map[string]bool{}
+	for _, name := range names {
+		h, ok := handlers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown handler %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("handler %q listed twice", name)
+		}
+		seen[name] = true
+		pipeline = append(pipeline, step{name: name, handler: h})
+	}
+	return pipeline, nil
+}
+
+// runPipeline runs the pipeline steps against env in order. When selected is non-nil, only
+// the steps it names run. Handler errors are logged and don't stop the remaining steps; they
+// are returned joined once every step has run. The outcome is written to the job summary
+// and, with PostSummaryComment, the changes made by the steps are summarized in a sticky PR
+// comment.
+func runPipeline(ctx context.Context, env *Env, pipeline []step, selected []string) error {
+	summary := &runSummary{}
+	ctx = contextWithSummary(ctx, summary)
+	key := "pr"
+	if env.PR == nil {
+		key = "issue"
+	}
+	logger := loggerFrom(ctx).With(key, env.Number())
+	var errs []error
+	enabled := map[string]bool{}
+	for _, name := range selected {
+		enabled[name] = true
+	}
+	for _, s := range pipeline {
+		if selected != nil && !enabled[s.name] {
+			continue
+		}
+		stepLogger := logger.With("handler", s.name)
+		err := s.handler.Handle(contextWithLogger(ctx, stepLogger), env)
+		if ctx.Err() != nil {
+			// The remaining handlers would fail as well.
+			err = fmt.Errorf("run timed out while handler %s was running: %w", s.name, ctx.Err())
+			stepLogger.Errorf("Run timed out while handler %s was running", s.name)
+			summary.addFailure(s.name, err)
+			errs = append(errs, err)
+			break
+		}
+		if err != nil {
+			stepLogger.Errorf("Handler %s failed: %v", s.name, err)
+			summary.addFailure(s.name, err)
+			errs = append(errs, err)
+		}
+	}
+	if env.Config.PostSummaryComment && ctx.Err() == nil {
+		postSummaryComment(contextWithLogger(ctx, logger), env, summary)
+	}
+	writeStepSummary(summary.markdown(env.Number(), env.Config.DryRun))
+	return errors.Join(errs...)
+}
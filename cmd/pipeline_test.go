@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-github/v45/github"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunPipeline(t *testing.T) {
+	failure := errors.New("request reviewers: 500 Internal Server Error")
+	var ran []string
+	handler := func(name string, err error) step {
+		return step{name: name, handler: HandlerFunc(func(ctx context.Context, env *Env) error {
+			ran = append(ran, name)
+			return err
+		})}
+	}
+	pipeline := []step{handler("title", nil), handler("reviewers", failure), handler("assignee", nil)}
+	env := testEnv(newFakeClient().Client, testConfig(t), nil)
+
+	if err := runPipeline(context.Background(), env, pipeline, nil); !errors.Is(err, failure) {
+		t.Errorf("runPipeline = %v, want the handler failure", err)
+	}
+	if want := []string{"title", "reviewers", "assignee"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	ran = nil
+	if err := runPipeline(context.Background(), env, pipeline, []string{"title", "assignee"}); err != nil {
+		t.Errorf("runPipeline of handlers with nothing to do = %v, want nil", err)
+	}
+	if want := []string{"title", "assignee"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+}
+
+func TestFilesHandlersShareChangedFiles(t *testing.T) {
+	client := newFakeClient()
+	client.pullRequests.files = []*github.CommitFile{changedFile("README.md", 1), changedFile("main.go", 2)}
+	var got [][]*github.CommitFile
+	handler := FilesHandlerFunc(func(ctx context.Context, env *Env, files []*github.CommitFile) error {
+		got = append(got, files)
+		return nil
+	})
+	pipeline := []step{{name: "size", handler: handler}, {name: "docs", handler: handler}}
+
+	if err := runPipeline(context.Background(), testEnv(client.Client, testConfig(t), nil), pipeline, nil); err != nil {
+		t.Fatalf("runPipeline: %v", err)
+	}
+	if client.pullRequests.fileCalls != 1 {
+		t.Errorf("listed changed files %d times, want once", client.pullRequests.fileCalls)
+	}
+	if len(got) != 2 || !reflect.DeepEqual(got[0], client.pullRequests.files) || !reflect.DeepEqual(got[1], client.pullRequests.files) {
+		t.Errorf("handlers got files %v, want the PR's files twice", got)
+	}
+}
+
+func TestRunPipelineTimeout(t *testing.T) {
+	var ran []string
+	pipeline := []step{
+		{name: "title", handler: HandlerFunc(func(ctx context.Context, env *Env) error {
+			ran = append(ran, "title")
+			return nil
+		})},
+		{name: "reviewers", handler: HandlerFunc(func(ctx context.Context, env *Env) error {
+			ran = append(ran, "reviewers")
+			// A hung API call returns once the run times out.
+			<-ctx.Done()
+			return ctx.Err()
+		})},
+		{name: "assignee", handler: HandlerFunc(func(ctx context.Context, env *Env) error {
+			ran = append(ran, "assignee")
+			return nil
+		})},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := runPipeline(ctx, testEnv(newFakeClient().Client, testConfig(t), nil), pipeline, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "handler reviewers") {
+		t.Errorf("runPipeline = %v, want a timeout naming the reviewers handler", err)
+	}
+	if want := []string{"title", "reviewers"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+}
+
+func TestTitleAndBranchLabelsAreStable(t *testing.T) {
+	client := newFakeClient()
+	cfg := testConfig(t)
+	cfg.BranchLabels = []keyValue{{Key: "feature/*", Value: "enhancement"}}
+	env := testEnv(client.Client, cfg, labels("bug", "enhancement"))
+	env.PR.Title = github.String("fix: handle empty body")
+	env.PR.Head = &github.PullRequestBranch{Ref: github.String("feature/x")}
+	pipeline, err := buildPipeline([]string{"title", "branch"})
+	if err != nil {
+		t.Fatalf("buildPipeline: %v", err)
+	}
+
+	if err := runPipeline(context.Background(), env, pipeline, nil); err != nil {
+		t.Fatalf("runPipeline: %v", err)
+	}
+	if len(client.issues.removed) != 0 || len(client.issues.added) != 0 {
+		t.Errorf("removed %v and added %v, want the labels left alone", client.issues.removed, client.issues.added)
+	}
+}
+
+func TestIssuePipeline(t *testing.T) {
+	pipeline, err := buildPipeline(defaultPipeline)
+	if err != nil {
+		t.Fatalf("buildPipeline: %v", err)
+	}
+	steps := issuePipeline(pipeline)
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.name)
+	}
+	if want := []string{"title", "assignee"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("issuePipeline = %v, want %v", names, want)
+	}
+
+	client := newFakeClient()
+	client.repositories.permissions["author"] = "write"
+	issue := &github.Issue{Number: github.Int(5), Title: github.String("fix: crash on start"), User: &github.User{Login: github.String("author")}}
+	cfg := testConfig(t)
+	cfg.PostSummaryComment = true
+	env := &Env{Client: client.Client, Owner: "o", Repo: "r", Issue: issue, Config: cfg}
+	if err := runPipeline(context.Background(), env, steps, nil); err != nil {
+		t.Fatalf("runPipeline: %v", err)
+	}
+	if !reflect.DeepEqual(client.issues.added, []string{"bug"}) || !reflect.DeepEqual(client.issues.assignees, []string{"author"}) {
+		t.Errorf("added %v and assigned %v, want bug and the author", client.issues.added, client.issues.assignees)
+	}
+	if len(client.issues.comments) != 1 || !strings.Contains(client.issues.comments[0].GetBody(), "updated this issue:") {
+		t.Errorf("summary comments %v, want one about this issue", client.issues.comments)
+	}
+}
+
+func TestTitleAndChangeTypeLabelsAreStable(t *testing.T) {
+	client := newFakeClient()
+	client.pullRequests.files = []*github.CommitFile{changedFile("README.md", 3)}
+	cfg := testConfig(t)
+	cfg.ChangeTypes = []ChangeType{{Label: "documentation", Paths: []string{"**/*.md", "*.md"}}}
+	env := testEnv(client.Client, cfg, labels("bug", "documentation"))
+	env.PR.Title = github.String("fix: typo in the install steps")
+	pipeline, err := buildPipeline([]string{"title", "change-type"})
+	if err != nil {
+		t.Fatalf("buildPipeline: %v", err)
+	}
+
+	if err := runPipeline(context.Background(), env, pipeline, nil); err != nil {
+		t.Fatalf("runPipeline: %v", err)
+	}
+	if len(client.issues.removed) != 0 || len(client.issues.added) != 0 {
+		t.Errorf("removed %v and added %v, want the labels left alone", client.issues.removed, client.issues.added)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// closingRefPattern matches GitHub closing keywords such as "Fixes #12" or "closes owner/repo#34".
+var closingRefPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:([\w.-]+)/([\w.-]+))?#(\d+)\b`)
+
+// issueRef identifies an issue, possibly in another repository.
+type issueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// parseClosingRefs extracts the issues a PR body closes. References without an explicit
+// repository resolve to owner/repo.
+func parseClosingRefs(body, owner, repo string) []issueRef {
+	var refs []issueRef
+	seen := map[issueRef]bool{}
+	for _, m := range closingRefPattern.FindAllStringSubmatch(body, -1) {
+		number, err := strconv.Atoi(m[3])
+		if err != nil {
+			continue
+		}
+		ref := issueRef{Owner: owner, Repo: repo, Number: number}
+		if m[1] != "" {
+			ref.Owner, ref.Repo = m[1], m[2]
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// isHighPriority reports whether the PR closes an issue carrying one of the priority labels.
+func isHighPriority(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest, priorityLabels []string) bool {
+	for _, ref := range parseClosingRefs(pr.GetBody(), owner, repo) {
+		issue, err := getIssue(ctx, client, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to get linked issue %s/%s#%d: %v", ref.Owner, ref.Repo, ref.Number, err)
+			continue
+		}
+		for _, l := range issue.Labels {
+			for _, p := range priorityLabels {
+				if strings.EqualFold(l.GetName(), p) {
+					loggerFrom(ctx).Infof("Linked issue %s/%s#%d has priority label: %s", ref.Owner, ref.Repo, ref.Number, l.GetName())
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"time"
+)
+
+// reconcileOpenPullRequests runs the reconcile handlers against every open PR, fixing
+// metadata that drifted since the PR events were processed. It returns an error when the
+// PRs couldn't be listed or a handler failed for any of them.
+func reconcileOpenPullRequests(ctx context.Context, client *Client, owner, repo string, cfg *Config, pipeline []step) error {
+	opts := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	processed, failed := 0, 0
+	for {
+		waitForRateLimit(ctx, client, cfg.ReconcileMinRateRemaining)
+		var prs []*github.PullRequest
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			prs, resp, err = client.PullRequests.List(ctx, owner, repo, opts)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("list open PRs: %w", err)
+		}
+		for _, pr := range prs {
+			if ctx.Err() != nil {
+				return fmt.Errorf("stopped after %d of the open PRs: %w", processed, ctx.Err())
+			}
+			if reason := skipReason(pr, cfg); reason != "" {
+				loggerFrom(ctx).Infof("Skipping PR #%d because %s", pr.GetNumber(), reason)
+				writeSkippedStepSummary(pr.GetNumber(), reason, cfg)
+				continue
+			}
+			waitForRateLimit(ctx, client, cfg.ReconcileMinRateRemaining)
+			loggerFrom(ctx).Infof("Reconciling PR #%d", pr.GetNumber())
+			env := &Env{Client: client, Owner: owner, Repo: repo, PR: pr, Config: cfg}
+			if err := runPipeline(ctx, env, pipeline, cfg.ReconcileHandlers); err != nil {
+				failed++
+			}
+			processed++
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	loggerFrom(ctx).Infof("Reconciled %d open PRs", processed)
+	if failed != 0 {
+		return fmt.Errorf("handlers failed for %d of %d open PRs", failed, processed)
+	}
+	return nil
+}
+
+// waitForRateLimit sleeps until the core rate limit resets when fewer than minRemaining
+// requests are left.
+func waitForRateLimit(ctx context.Context, client *Client, minRemaining int) {
+	limits, _, err := client.RateLimits(ctx)
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to read rate limits: %v", err)
+		return
+	}
+	core := limits.GetCore()
+	if core == nil || core.Remaining >= minRemaining {
+		return
+	}
+	wait := time.Until(core.Reset.Time)
+	if wait <= 0 {
+		return
+	}
+	loggerFrom(ctx).Warnf("Rate limit nearly exhausted (%d remaining), waiting %s", core.Remaining, wait.Round(time.Second))
+	select {
+	case <-ctx.Done():
+	case <-time.After(wait):
+	}
+}
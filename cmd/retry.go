@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-github/v45/github"
+	"time"
+)
+
+const (
+	// defaultRetryAttempts is the number of attempts withRetry makes unless the context
+	// carries another count.
+	defaultRetryAttempts = 3
+	// maxRetryWait is the longest withRetry sleeps before an attempt. A primary rate limit
+	// that resets later than this fails immediately instead of stalling the job.
+	maxRetryWait = time.Minute
+)
+
+// retryAttemptsKey is the context key of the attempt count.
+type retryAttemptsKey struct{}
+
+// contextWithRetryAttempts returns a context whose withRetry calls make up to attempts attempts.
+func contextWithRetryAttempts(ctx context.Context, attempts int) context.Context {
+	return context.WithValue(ctx, retryAttemptsKey{}, attempts)
+}
+
+// withRetry calls fn, retrying it while GitHub reports a primary or secondary rate limit.
+// It waits as long as GitHub asks, or backs off exponentially from 15s when it doesn't say.
+// Other errors are returned immediately.
+func withRetry(ctx context.Context, fn func() error) error {
+	attempts := defaultRetryAttempts
+	if n, ok := ctx.Value(retryAttemptsKey{}).(int); ok {
+		attempts = n
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		wait, ok := retryWait(err, attempt)
+		if !ok || attempt >= attempts || wait > maxRetryWait {
+			return err
+		}
+		loggerFrom(ctx).Warnf("GitHub rate limit hit, retrying in %s (attempt %d of %d): %v", wait, attempt+1, attempts, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryWait reports whether err is a rate limit error and how long to wait before the next
+// attempt.
+func retryWait(err error, attempt int) (time.Duration, bool) {
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return time.Duration(1<<(attempt-1)) * 15 * time.Second, true
+	}
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return time.Until(rateErr.Rate.Reset.Time) + time.Second, true
+	}
+	return 0, false
+}
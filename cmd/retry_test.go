@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-github/v45/github"
+	"testing"
+	"time"
+)
+
+func TestRetryWait(t *testing.T) {
+	retryAfter := 5 * time.Second
+	reset := github.Timestamp{Time: time.Now().Add(30 * time.Second)}
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		min     time.Duration
+		max     time.Duration
+		retry   bool
+	}{
+		{name: "secondary limit with Retry-After", err: &github.AbuseRateLimitError{RetryAfter: &retryAfter}, attempt: 1, min: retryAfter, max: retryAfter, retry: true},
+		{name: "secondary limit, first backoff", err: &github.AbuseRateLimitError{}, attempt: 1, min: 15 * time.Second, max: 15 * time.Second, retry: true},
+		{name: "secondary limit, third backoff", err: &github.AbuseRateLimitError{}, attempt: 3, min: time.Minute, max: time.Minute, retry: true},
+		{name: "primary limit waits for the reset", err: &github.RateLimitError{Rate: github.Rate{Reset: reset}}, attempt: 1, min: 29 * time.Second, max: 31 * time.Second, retry: true},
+		{name: "other error", err: errorResponse(500), attempt: 1},
+		{name: "no error", attempt: 1},
+	}
+	for _, tt := range tests {
+		wait, retry := retryWait(tt.err, tt.attempt)
+		if retry != tt.retry || wait < tt.min || wait > tt.max {
+			t.Errorf("%s: retryWait = %s, %v, want %s to %s, %v", tt.name, wait, retry, tt.min, tt.max, tt.retry)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	noWait := time.Duration(0)
+	limited := &github.AbuseRateLimitError{RetryAfter: &noWait}
+	longWait := 2 * maxRetryWait
+	stalled := &github.AbuseRateLimitError{RetryAfter: &longWait}
+	other := errors.New("not a rate limit")
+	tests := []struct {
+		name      string
+		attempts  int
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "success", attempts: 3, errs: []error{nil}, wantCalls: 1},
+		{name: "succeeds after a rate limit", attempts: 3, errs: []error{limited, nil}, wantCalls: 2},
+		{name: "gives up after the attempts", attempts: 3, errs: []error{limited, limited, limited, nil}, wantCalls: 3, wantErr: limited},
+		{name: "single attempt", attempts: 1, errs: []error{limited, nil}, wantCalls: 1, wantErr: limited},
+		{name: "other errors are not retried", attempts: 3, errs: []error{other, nil}, wantCalls: 1, wantErr: other},
+		{name: "wait above the maximum", attempts: 3, errs: []error{stalled, nil}, wantCalls: 1, wantErr: stalled},
+	}
+	for _, tt := range tests {
+		calls := 0
+		ctx := contextWithRetryAttempts(context.Background(), tt.attempts)
+		err := withRetry(ctx, func() error {
+			calls++
+			return tt.errs[calls-1]
+		})
+		if calls != tt.wantCalls {
+			t.Errorf("%s: %d calls, want %d", tt.name, calls, tt.wantCalls)
+		}
+		if err != tt.wantErr {
+			t.Errorf("%s: withRetry = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWithRetryCanceled(t *testing.T) {
+	wait := 30 * time.Second
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := withRetry(ctx, func() error {
+		calls++
+		return &github.AbuseRateLimitError{RetryAfter: &wait}
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("withRetry with a canceled context = %v after %d calls, want context.Canceled after 1", err, calls)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"regexp"
+	"strconv"
+)
+
+// revertTitlePattern matches both GitHub's `Revert "..."` titles and the `revert:` prefix.
+var revertTitlePattern = regexp.MustCompile(`(?i)^\s*revert\b`)
+
+// revertedPRPattern matches GitHub's default revert body, e.g. "Reverts owner/repo#123".
+var revertedPRPattern = regexp.MustCompile(`(?i)\breverts\s+(?:[\w.-]+/[\w.-]+)?#(\d+)`)
+
+// revertedCommitPattern matches git's default revert message, e.g. "This reverts commit abc123".
+var revertedCommitPattern = regexp.MustCompile(`(?i)\breverts\s+commit\s+([0-9a-f]{7,40})\b`)
+
+// revertMarker identifies the comment notifying the author of a reverted change.
+const revertMarker = "<!-- auto-assign:revert -->"
+
+// isRevertTitle reports whether a title marks the PR as a revert.
+func isRevertTitle(title string) bool {
+	return revertTitlePattern.MatchString(title)
+}
+
+// notifyRevertedAuthor mentions the author of the reverted PR or commit referenced in the
+// PR body, so they learn their change is being backed out.
+func notifyRevertedAuthor(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest, cfg *Config) {
+	body := pr.GetBody()
+	var original, reference string
+	if m := revertedPRPattern.FindStringSubmatch(body); m != nil {
+		number, _ := strconv.Atoi(m[1])
+		reverted, err := getPullRequest(ctx, client, owner, repo, number)
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to get reverted PR #%d: %v", number, err)
+			return
+		}
+		original, reference = reverted.GetUser().GetLogin(), "#"+m[1]
+	} else if m := revertedCommitPattern.FindStringSubmatch(body); m != nil {
+		var commit *github.RepositoryCommit
+		err := withRetry(ctx, func() (err error) {
+			commit, _, err = client.Repositories.GetCommit(ctx, owner, repo, m[1], nil)
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to get reverted commit %s: %v", m[1], err)
+			return
+		}
+		original, reference = commit.GetAuthor().GetLogin(), m[1]
+	}
+
+	if original == "" {
+		loggerFrom(ctx).Infof("No reverted PR or commit found in the PR body")
+		return
+	}
+	if original == pr.GetUser().GetLogin() {
+		loggerFrom(ctx).Infof("PR author reverts their own change, skipping notification")
+		return
+	}
+
+	if cfg.DryRun {
+		loggerFrom(ctx).Infof("%s would notify %s about the revert of %s", dryRunPrefix, original, reference)
+		return
+	}
+	text := fmt.Sprintf("@%s, this PR reverts your change in %s.", original, reference)
+	if err := upsertComment(ctx, client, owner, repo, pr.GetNumber(), revertMarker, text); err != nil {
+		loggerFrom(ctx).Warnf("Failed to notify reverted author: %v", err)
+	} else {
+		loggerFrom(ctx).Infof("Notified %s about the revert of %s", original, reference)
+	}
+}
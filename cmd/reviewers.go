@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"net/http"
+	"strings"
+)
+
+// reviewerSource produces candidate reviewers for a PR. An empty result defers to the
+// next source in precedence order. Candidates of sources that aren't known collaborators
+// are checked for repository access before they are requested. The round-robin rotation is
+// only saved when a rotating source's reviewers are requested.
+type reviewerSource struct {
+	name          string
+	resolve       func() []string
+	collaborators bool
+	rotates       bool
+}
+
+// assignDefaultReviewers requests reviewers from the first source, in precedence order,
+// that produces any:
+//
+//  1. priority: the priority reviewers of a PR closing a high-priority issue;
+//  2. team routes: teams owning the changed paths;
+//  3. code owners: the CODEOWNERS owners of the changed files;
+//  4. file history: authors and reviewers of merged PRs touching the same files;
+//  5. previous reviewers: users who already reviewed the PR, under PREFER_PREVIOUS_REVIEWERS;
+//  6. catch-all: CATCH_ALL_REVIEWERS, for PRs no routing rule covers;
+//  7. reviewer pool: a sample of REVIEWER_POOL and the members of REVIEWER_TEAMS, when set;
+//  8. collaborators: otherwise, a sample of repository collaborators, ordered by REVIEWER_STRATEGY.
+//
+// Unless PREFER_PREVIOUS_REVIEWERS is set, users who already submitted a review of the PR are
+// never requested again.
+// With CROSS_TEAM_REVIEW, the history, catch-all, pool, and collaborator candidates exclude members of
+// the author's teams. The outcome is optionally reported as a check run. A MaxReviewers of 0
+// disables reviewer assignment, and draft PRs get no reviewers under SkipDraftReviewers, nor
+// PRs with merge conflicts under SkipConflictedReviewers.
+func assignDefaultReviewers(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	if env.Config.MaxReviewers == 0 {
+		loggerFrom(ctx).Infof("Reviewer assignment is disabled by MAX_REVIEWERS=0")
+		summaryFrom(ctx).addSkipped("reviewers: disabled by MAX_REVIEWERS=0")
+		return nil
+	}
+	if env.Config.SkipDraftReviewers && env.PR.GetDraft() {
+		loggerFrom(ctx).Infof("Skipping reviewer assignment because the PR is a draft; reviewers are requested once it is ready for review")
+		summaryFrom(ctx).addSkipped("reviewers: the PR is a draft")
+		return nil
+	}
+	if env.Config.SkipConflictedReviewers {
+		if conflicted, err := handleMergeConflict(ctx, env); conflicted {
+			summaryFrom(ctx).addSkipped("reviewers: the PR has merge conflicts")
+			return err
+		}
+	}
+	assigned, err := requestDefaultReviewers(ctx, env, files)
+	if env.Config.CheckRun {
+		reportAssignmentCheck(ctx, env.Client, env.Owner, env.Repo, env.PR, assigned, env.Config)
+	}
+	return err
+}
+
+// requestDefaultReviewers resolves and requests the reviewers, reporting whether the PR has
+// reviewers once it returns.
+func requestDefaultReviewers(ctx context.Context, env *Env, files []*github.CommitFile) (bool, error) {
+	pr, cfg := env.PR, env.Config
+	if len(pr.RequestedReviewers) != 0 {
+		loggerFrom(ctx).Infof("PR already has reviewers")
+		summaryFrom(ctx).addSkipped("reviewers: the PR already has reviewers")
+		return true, nil
+	}
+
+	author := pr.GetUser().GetLogin()
+
+	// People who already reviewed drop off the requested list, but shouldn't be asked again,
+	// unless PreferPreviousReviewers asks for them to see the update.
+	reviewed := submittedReviewers(ctx, env.Client, env.Owner, env.Repo, env.Number())
+	exclude := reviewed
+	if cfg.PreferPreviousReviewers {
+		exclude = nil
+	}
+	_, preferred := env.priorityRouting(ctx)
+	limit := reviewerLimit(pr, cfg)
+	crossTeam := newCrossTeamFilter(ctx, env.Client, env.Owner, author, cfg)
+	usePool := len(cfg.ReviewerPool) != 0 || len(cfg.ReviewerTeams) != 0
+	rng := reviewerRand(cfg, env.Number())
+	// sample orders the pool or collaborator candidates by REVIEWER_STRATEGY.
+	sample := func(candidates []string) []string {
+		var load map[string]int
+		if cfg.ReviewerStrategy == strategyBalanced {
+			load = reviewLoad(ctx, env.Client, env.Owner, env.Repo)
+		}
+		return orderCandidates(rng, excludeUsers(candidates, exclude), author, cfg, load)
+	}
+	var rotation rotationState
+	var codeownersErr, collaboratorsErr error
+	sources := []reviewerSource{
+		{name: "priority", resolve: func() []string {
+			return excludeUser(preferred, author)
+		}},
+		{name: "team routes", rotates: true, resolve: func() []string {
+			if len(cfg.TeamRoutes) == 0 {
+				return nil
+			}
+			var reviewers []string
+			reviewers, rotation = routeReviewers(ctx, rng, env.Client, env.Owner, env.Repo, pr, files, exclude, cfg.MaxReviewers, cfg)
+			return reviewers
+		}},
+		{name: "code owners", resolve: func() []string {
+			if !cfg.Codeowners {
+				return nil
+			}
+			var reviewers []string
+			reviewers, codeownersErr = codeownerReviewers(ctx, env.Client, env.Owner, env.Repo, pr, files)
+			return reviewers
+		}},
+		{name: "file history", resolve: func() []string {
+			if !cfg.HistoryReviewers {
+				return nil
+			}
+			history := excludeUsers(excludeIgnored(historyReviewers(ctx, env.Client, env.Owner, env.Repo, pr, files, cfg), cfg), exclude)
+			return capReviewers(crossTeam.apply(history), limit)
+		}},
+		{name: "previous reviewers", resolve: func() []string {
+			if !cfg.PreferPreviousReviewers {
+				return nil
+			}
+			return capReviewers(excludeUser(reviewed, author), limit)
+		}},
+		{name: "catch-all", resolve: func() []string {
+			return crossTeam.apply(excludeUser(cfg.CatchAllReviewers, author))
+		}},
+		{name: "reviewer pool", resolve: func() []string {
+			if !usePool {
+				return nil
+			}
+			pool := crossTeam.apply(poolReviewers(ctx, env.Client, env.Owner, author, cfg))
+			return capReviewers(sample(pool), limit)
+		}},
+		{name: "collaborators", collaborators: true, resolve: func() []string {
+			if usePool {
+				return nil
+			}
+			collaborators, err := collaboratorReviewers(ctx, env.Client, env.Owner, env.Repo, author, cfg)
+			collaboratorsErr = err
+			return capReviewers(sample(crossTeam.apply(collaborators)), limit)
+		}},
+	}
+
+	for _, source := range sources {
+		reviewers := excludeUsers(excludeIgnored(source.resolve(), cfg), exclude)
+		// Falling back to the next source would ask reviewers CODEOWNERS may not name.
+		if codeownersErr != nil {
+			return false, fmt.Errorf("code owners: %w", codeownersErr)
+		}
+		if !source.collaborators {
+			reviewers = reviewableUsers(ctx, env.Client, env.Owner, env.Repo, reviewers)
+		}
+		if len(reviewers) == 0 {
+			continue
+		}
+		loggerFrom(ctx).With("source", source.name).Infof("Selected reviewers from %s", source.name)
+		if err := requestReviewers(ctx, env.Client, env.Owner, env.Repo, env.Number(), reviewers, cfg); err != nil {
+			return false, err
+		}
+		if source.rotates {
+			saveRotationState(ctx, env.Client, env.Owner, env.Repo, cfg.RoundRobinStateIssue, rotation, cfg)
+		}
+		return true, nil
+	}
+
+	if cfg.NoReviewersComment {
+		notifyNoReviewers(ctx, env.Client, env.Owner, env.Repo, env.Number(), cfg)
+	}
+	// Without collaborators to fall back on, a failure to list them is why nobody was found.
+	if collaboratorsErr != nil {
+		return false, fmt.Errorf("no reviewers found: list collaborators: %w", collaboratorsErr)
+	}
+	loggerFrom(ctx).Infof("No collaborators found")
+	summaryFrom(ctx).addSkipped("reviewers: no candidates found")
+	return false, nil
+}
+
+// reviewerLimit returns how many reviewers sampled sources may request: MaxReviewers, unless
+// the PR template answer maps to a different count.
+func reviewerLimit(pr *github.PullRequest, cfg *Config) int {
+	if n := templateReviewerCount(pr, cfg); n > 0 {
+		return n
+	}
+	return cfg.MaxReviewers
+}
+
+// collaboratorReviewers lists the repository collaborators other than the author. When the
+// token lacks permission to list them, the configured fallback pool is used instead;
+// otherwise a listing error is returned with the collaborators listed before it.
+func collaboratorReviewers(ctx context.Context, client *Client, owner, repo, author string, cfg *Config) ([]string, error) {
+	opts := &github.ListCollaboratorsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var collaborators []string
+	for {
+		var collaborator []*github.User
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			collaborator, resp, err = client.Repositories.ListCollaborators(ctx, owner, repo, opts)
+			return err
+		})
+		if err != nil {
+			if isPermissionError(err) && len(cfg.FallbackReviewers) != 0 {
+				loggerFrom(ctx).Warnf("Token cannot list collaborators (%v); grant it read access to repository metadata and collaborators, or keep using FALLBACK_REVIEWERS. Using the fallback reviewer pool", err)
+				return excludeIgnored(excludeUser(cfg.FallbackReviewers, author), cfg), nil
+			} else if isPermissionError(err) {
+				loggerFrom(ctx).Warnf("Token cannot list collaborators (%v); grant it read access to repository metadata and collaborators, or set FALLBACK_REVIEWERS", err)
+			}
+			return excludeIgnored(collaborators, cfg), err
+		}
+		for _, c := range collaborator {
+			if c.GetLogin() == author || c.GetType() == "Bot" {
+				continue
+			}
+			collaborators = append(collaborators, c.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if cfg.MinContributions > 1 {
+		collaborators = filterByContributions(ctx, client, owner, repo, collaborators, cfg.MinContributions)
+	}
+	return excludeIgnored(collaborators, cfg), nil
+}
+
+// filterByContributions returns the candidates with at least minContributions commits to the repository,
+// so that people with a single typo fix aren't asked to review. When the contributors can't
+// be listed, every candidate is kept.
+func filterByContributions(ctx context.Context, client *Client, owner, repo string, candidates []string, minContributions int) []string {
+	contributions := map[string]int{}
+	opts := &github.ListContributorsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		var contributors []*github.Contributor
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			contributors, resp, err = client.Repositories.ListContributors(ctx, owner, repo, opts)
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to list contributors, not applying MIN_CONTRIBUTIONS: %v", err)
+			return candidates
+		}
+		for _, c := range contributors {
+			contributions[strings.ToLower(c.GetLogin())] += c.GetContributions()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	var eligible []string
+	for _, login := range candidates {
+		if contributions[strings.ToLower(login)] >= minContributions {
+			eligible = append(eligible, login)
+		}
+	}
+	if filtered := len(candidates) - len(eligible); filtered > 0 {
+		loggerFrom(ctx).Infof("Left out %d of %d collaborators with fewer than %d contributions", filtered, len(candidates), minContributions)
+	}
+	return eligible
+}
+
+// poolReviewers returns the configured reviewer pool: the REVIEWER_POOL logins and the
+// members of the REVIEWER_TEAMS teams of org, without the author or ignored reviewers.
+func poolReviewers(ctx context.Context, client *Client, org, author string, cfg *Config) []string {
+	var pool []string
+	add := func(logins []string) {
+		for _, login := range logins {
+			if !containsFold(pool, login) {
+				pool = append(pool, login)
+			}
+		}
+	}
+	add(cfg.ReviewerPool)
+	for _, slug := range cfg.ReviewerTeams {
+		var members []string
+		err := withRetry(ctx, func() (err error) {
+			members, err = listTeamMembers(ctx, client, org, slug)
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to list members of team %s/%s: %v", org, slug, err)
+			continue
+		}
+		add(members)
+	}
+	return excludeIgnored(excludeUser(pool, author), cfg)
+}
+
+// excludeUser returns a copy of users without login.
+func excludeUser(users []string, login string) []string {
+	var filtered []string
+	for _, u := range users {
+		if !strings.EqualFold(u, login) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered
+}
+
+// excludeUsers returns a copy of users without any of logins.
+func excludeUsers(users, logins []string) []string {
+	var filtered []string
+	for _, u := range users {
+		if !containsFold(logins, u) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered
+}
+
+// submittedReviewers returns the users who submitted a review of the PR.
+func submittedReviewers(ctx context.Context, client *Client, owner, repo string, number int) []string {
+	opts := &github.ListOptions{PerPage: 100}
+	var reviewers []string
+	for {
+		var reviews []*github.PullRequestReview
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			reviews, resp, err = client.PullRequests.ListReviews(ctx, owner, repo, number, opts)
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to list reviews: %v", err)
+			return reviewers
+		}
+		for _, r := range reviews {
+			if login := r.GetUser().GetLogin(); login != "" && r.GetState() != "PENDING" && !containsFold(reviewers, login) {
+				reviewers = append(reviewers, login)
+			}
+		}
+		if resp.NextPage == 0 {
+			return reviewers
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// excludeIgnored removes bot accounts and IGNORED_REVIEWERS from users, so they are never
+// requested automatically.
+func excludeIgnored(users []string, cfg *Config) []string {
+	var filtered []string
+	for _, u := range users {
+		if !isIgnoredReviewer(u, cfg) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered
+}
+
+// isIgnoredReviewer reports whether u is a bot account or listed in IgnoredReviewers.
+func isIgnoredReviewer(u string, cfg *Config) bool {
+	return strings.HasSuffix(strings.ToLower(u), "[bot]") || containsFold(cfg.IgnoredReviewers, u)
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// capReviewers truncates reviewers to at most max entries. A negative max leaves them uncapped.
+func capReviewers(reviewers []string, max int) []string {
+	if max >= 0 && len(reviewers) > max {
+		return reviewers[:max]
+	}
+	return reviewers
+}
+
+// isNotFound reports whether err is a GitHub API response for a missing resource.
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+// isPermissionError reports whether err is a GitHub API response denying access to a resource.
+func isPermissionError(err error) bool {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return false
+	}
+	switch errResp.Response.StatusCode {
+	case http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// requestReviewers requests reviews from the given users. Entries of the form "org/team"
+// are requested as team reviewers.
+func requestReviewers(ctx context.Context, client *Client, owner, repo string, prNumber int, reviewers []string, cfg *Config) error {
+	if cfg.DryRun {
+		for _, r := range reviewers {
+			loggerFrom(ctx).With("reviewer", r).Infof("%s would request reviewer: %s", dryRunPrefix, r)
+		}
+		summaryFrom(ctx).addReviewers(reviewers...)
+		return nil
+	}
+	var reviewersRequest github.ReviewersRequest
+	for _, r := range reviewers {
+		if _, team, ok := strings.Cut(r, "/"); ok {
+			reviewersRequest.TeamReviewers = append(reviewersRequest.TeamReviewers, team)
+		} else {
+			reviewersRequest.Reviewers = append(reviewersRequest.Reviewers, r)
+		}
+	}
+	err := withRetry(ctx, func() error {
+		_, _, err := client.PullRequests.RequestReviewers(ctx, owner, repo, prNumber, reviewersRequest)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("add default reviewers: %w", err)
+	}
+	loggerFrom(ctx).With("reviewers", reviewers).Infof("Default reviewers added: %v", reviewers)
+	summaryFrom(ctx).addReviewers(reviewers...)
+	return nil
+}
+
+// noReviewersMarker identifies the comment posted when reviewer assignment fails.
+const noReviewersMarker = "<!-- auto-assign:no-reviewers -->"
+
+// notifyNoReviewers posts (or updates) a comment alerting maintainers that no reviewers were assigned.
+func notifyNoReviewers(ctx context.Context, client *Client, owner, repo string, prNumber int, cfg *Config) {
+	if cfg.DryRun {
+		loggerFrom(ctx).Infof("%s would post no-reviewers comment", dryRunPrefix)
+		return
+	}
+	if err := upsertComment(ctx, client, owner, repo, prNumber, noReviewersMarker, cfg.NoReviewersCommentText); err != nil {
+		loggerFrom(ctx).Warnf("Failed to post no-reviewers comment: %v", err)
+	} else {
+		loggerFrom(ctx).Infof("Posted no-reviewers comment")
+	}
+}
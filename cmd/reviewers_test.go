@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAssignDefaultReviewersPrecedence(t *testing.T) {
+	tests := []struct {
+		name          string
+		configure     func(*Config)
+		codeowners    string
+		collaborators []string
+		reviewed      []string
+		want          []string
+		wantTeams     []string
+	}{
+		{
+			name:          "collaborators",
+			collaborators: []string{"author", "dave"},
+			want:          []string{"dave"},
+		},
+		{
+			name:          "catch-all over collaborators",
+			configure:     func(cfg *Config) { cfg.CatchAllReviewers = []string{"carol", "author"} },
+			collaborators: []string{"dave"},
+			want:          []string{"carol"},
+		},
+		{
+			name:          "code owners over catch-all",
+			configure:     func(cfg *Config) { cfg.CatchAllReviewers = []string{"carol"} },
+			codeowners:    "* @bob @org/core",
+			collaborators: []string{"dave"},
+			want:          []string{"bob"},
+			wantTeams:     []string{"core"},
+		},
+		{
+			name: "team routes over code owners",
+			configure: func(cfg *Config) {
+				cfg.TeamRoutes = []TeamRoute{{Name: "api", Paths: []string{"**"}, Members: []string{"erin"}}}
+			},
+			codeowners: "* @bob",
+			want:       []string{"erin"},
+		},
+		{
+			name: "reviewer pool over collaborators",
+			configure: func(cfg *Config) {
+				cfg.ReviewerPool = []string{"author", "bob"}
+				cfg.ReviewerTeams = []string{"backend", "missing"}
+				cfg.MaxReviewers = 3
+			},
+			collaborators: []string{"dave"},
+			want:          []string{"bob", "carol"},
+		},
+		{
+			name:          "already reviewed",
+			codeowners:    "* @bob @carol",
+			collaborators: []string{"bob", "dave"},
+			reviewed:      []string{"Bob"},
+			want:          []string{"carol"},
+		},
+		{
+			name:          "everyone reviewed",
+			configure:     func(cfg *Config) { cfg.CatchAllReviewers = []string{"carol"} },
+			collaborators: []string{"dave", "erin"},
+			reviewed:      []string{"carol", "dave"},
+			want:          []string{"erin"},
+		},
+		{
+			name:          "previous reviewers",
+			configure:     func(cfg *Config) { cfg.PreferPreviousReviewers = true },
+			collaborators: []string{"erin", "frank"},
+			reviewed:      []string{"author", "carol", "renovate[bot]", "dave"},
+			want:          []string{"carol", "dave"},
+		},
+		{
+			name: "previous reviewers over catch-all",
+			configure: func(cfg *Config) {
+				cfg.PreferPreviousReviewers = true
+				cfg.CatchAllReviewers = []string{"erin"}
+			},
+			collaborators: []string{"frank"},
+			reviewed:      []string{"carol"},
+			want:          []string{"carol"},
+		},
+		{
+			name:          "no previous reviewers",
+			configure:     func(cfg *Config) { cfg.PreferPreviousReviewers = true },
+			collaborators: []string{"author", "erin"},
+			want:          []string{"erin"},
+		},
+		{
+			name:          "ignored and bot reviewers",
+			configure:     func(cfg *Config) { cfg.IgnoredReviewers = []string{"Dave"} },
+			collaborators: []string{"dave", "renovate[bot]", "frank"},
+			want:          []string{"frank"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			client := newFakeClient()
+			client.pullRequests.files = []*github.CommitFile{changedFile("main.go", 10)}
+			client.repositories.collaborators = tt.collaborators
+			for _, login := range tt.reviewed {
+				client.pullRequests.reviews = append(client.pullRequests.reviews, &github.PullRequestReview{User: &github.User{Login: github.String(login)}, State: github.String("APPROVED")})
+			}
+			client.teams.members["backend"] = []string{"carol", "Bob", "mallory"}
+			for _, u := range []string{"bob", "carol", "dave", "erin", "frank"} {
+				client.repositories.permissions[u] = "write"
+			}
+			if tt.codeowners != "" {
+				client.repositories.contents[".github/CODEOWNERS"] = tt.codeowners
+			}
+
+			if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), client.pullRequests.files); err != nil {
+				t.Fatalf("assignDefaultReviewers: %v", err)
+			}
+			if len(client.pullRequests.requested) != 1 {
+				t.Fatalf("got %d review requests, want 1", len(client.pullRequests.requested))
+			}
+			// Sampled sources are shuffled.
+			got := client.pullRequests.requested[0]
+			sort.Strings(got.Reviewers)
+			if !reflect.DeepEqual(got.Reviewers, tt.want) || !reflect.DeepEqual(got.TeamReviewers, tt.wantTeams) {
+				t.Errorf("requested %v and teams %v, want %v and teams %v", got.Reviewers, got.TeamReviewers, tt.want, tt.wantTeams)
+			}
+		})
+	}
+}
+
+func TestAssignDefaultReviewersSkips(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxReviewers = 0
+	client := newFakeClient()
+	if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), client.pullRequests.files); err != nil {
+		t.Fatalf("assignDefaultReviewers: %v", err)
+	}
+
+	cfg = testConfig(t)
+	env := testEnv(client.Client, cfg, nil)
+	env.PR.Draft = github.Bool(true)
+	if err := assignDefaultReviewers(context.Background(), env, client.pullRequests.files); err != nil {
+		t.Fatalf("assignDefaultReviewers: %v", err)
+	}
+	if len(client.pullRequests.requested) != 0 {
+		t.Errorf("requested reviewers %v, want none", client.pullRequests.requested)
+	}
+}
+
+func TestAssignDefaultReviewersListFailure(t *testing.T) {
+	cfg := testConfig(t)
+	client := newFakeClient()
+	client.repositories.collaboratorsErr = errorResponse(http.StatusInternalServerError)
+	if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), client.pullRequests.files); err == nil {
+		t.Errorf("assignDefaultReviewers succeeded although collaborators could not be listed")
+	}
+
+	cfg.FallbackReviewers = []string{"bob"}
+	client.repositories.collaboratorsErr = errorResponse(http.StatusForbidden)
+	if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), client.pullRequests.files); err != nil {
+		t.Fatalf("assignDefaultReviewers with fallback reviewers: %v", err)
+	}
+	if len(client.pullRequests.requested) != 1 || !reflect.DeepEqual(client.pullRequests.requested[0].Reviewers, []string{"bob"}) {
+		t.Errorf("requested %v, want the fallback reviewer", client.pullRequests.requested)
+	}
+}
+
+func TestAssignDefaultReviewersReproducible(t *testing.T) {
+	collaborators := []string{"bob", "carol", "dave", "erin", "frank", "grace"}
+	pick := func(seed, prNumber int) []string {
+		cfg := testConfig(t)
+		cfg.Codeowners = false
+		cfg.MaxReviewers = 2
+		cfg.ReviewerSeed = seed
+		client := newFakeClient()
+		client.repositories.collaborators = collaborators
+		env := testEnv(client.Client, cfg, nil)
+		env.PR.Number = github.Int(prNumber)
+		if err := assignDefaultReviewers(context.Background(), env, nil); err != nil {
+			t.Fatalf("assignDefaultReviewers: %v", err)
+		}
+		if len(client.pullRequests.requested) != 1 {
+			t.Fatalf("got %d review requests, want 1", len(client.pullRequests.requested))
+		}
+		return client.pullRequests.requested[0].Reviewers
+	}
+
+	first := pick(0, 7)
+	if len(first) != 2 {
+		t.Fatalf("requested %v, want 2 reviewers", first)
+	}
+	if again := pick(0, 7); !reflect.DeepEqual(again, first) {
+		t.Errorf("re-run requested %v, want the same reviewers %v", again, first)
+	}
+	if seeded := pick(7, 42); !reflect.DeepEqual(seeded, first) {
+		t.Errorf("REVIEWER_SEED=7 requested %v, want the reviewers of the PR number 7 seed %v", seeded, first)
+	}
+}
+
+func TestAssignDefaultReviewersPriorityOverTeamRoutes(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.PriorityReviewers = []string{"oncall"}
+	cfg.TeamRoutes = []TeamRoute{{Name: "api", Paths: []string{"**"}, Members: []string{"erin"}}}
+	client := newFakeClient()
+	client.issues.issue = &github.Issue{Number: github.Int(3), Labels: labels("P0")}
+	for _, u := range []string{"oncall", "erin"} {
+		client.repositories.permissions[u] = "write"
+	}
+	env := testEnv(client.Client, cfg, nil)
+	env.PR.Body = github.String("Fixes #3")
+
+	if err := assignDefaultReviewers(context.Background(), env, []*github.CommitFile{changedFile("main.go", 1)}); err != nil {
+		t.Fatalf("assignDefaultReviewers: %v", err)
+	}
+	if len(client.pullRequests.requested) != 1 {
+		t.Fatalf("got %d review requests, want 1", len(client.pullRequests.requested))
+	}
+	if got, want := client.pullRequests.requested[0].Reviewers, []string{"oncall"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requested %v, want the priority reviewers %v", got, want)
+	}
+}
+
+func TestAssignDefaultReviewersTeamQuorum(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxReviewers = 5
+	cfg.TemplateQuestion = "Risk level"
+	cfg.TemplateAnswerReviewers = []keyValue{{Key: "low", Value: "1"}}
+	members := []string{"alice", "bob", "carol", "dave"}
+	cfg.TeamRoutes = []TeamRoute{{Name: "core", Paths: []string{"**"}, Members: members, Quorum: 2}}
+	client := newFakeClient()
+	for _, u := range members {
+		client.repositories.permissions[u] = "write"
+	}
+	env := testEnv(client.Client, cfg, nil)
+	env.PR.Body = github.String("Risk level: low")
+
+	if err := assignDefaultReviewers(context.Background(), env, []*github.CommitFile{changedFile("main.go", 1)}); err != nil {
+		t.Fatalf("assignDefaultReviewers: %v", err)
+	}
+	if len(client.pullRequests.requested) != 1 {
+		t.Fatalf("got %d review requests, want 1", len(client.pullRequests.requested))
+	}
+	// A quorum of 2 requests 3 members; the template count of 1 doesn't apply to team routes.
+	if got := client.pullRequests.requested[0].Reviewers; len(got) != 3 {
+		t.Errorf("requested %v, want 3 core reviewers", got)
+	}
+}
+
+func TestAssignDefaultReviewersRotationState(t *testing.T) {
+	tests := []struct {
+		name      string
+		access    []string
+		issueErr  error
+		wantSaved bool
+	}{
+		{name: "team routes requested", access: []string{"alice", "bob", "carol"}, wantSaved: true},
+		{name: "catch-all requested", access: []string{"carol"}},
+		{name: "state issue unreadable", access: []string{"alice", "bob", "carol"}, issueErr: errorResponse(http.StatusBadGateway)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Codeowners = false
+			cfg.RoundRobinStateIssue = 10
+			cfg.CatchAllReviewers = []string{"carol"}
+			cfg.TeamRoutes = []TeamRoute{{Name: "core", Paths: []string{"**"}, Members: []string{"alice", "bob"}, RoundRobin: true}}
+			client := newFakeClient()
+			client.issues.issue = &github.Issue{Number: github.Int(10), Body: github.String("Round-robin state")}
+			client.issues.issueErr = tt.issueErr
+			for _, u := range tt.access {
+				client.repositories.permissions[u] = "write"
+			}
+
+			if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), []*github.CommitFile{changedFile("main.go", 1)}); err != nil {
+				t.Fatalf("assignDefaultReviewers: %v", err)
+			}
+			if len(client.pullRequests.requested) != 1 {
+				t.Fatalf("got %d review requests, want 1", len(client.pullRequests.requested))
+			}
+			if saved := len(client.issues.edits) != 0; saved != tt.wantSaved {
+				t.Errorf("saved rotation state = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestOrderCandidatesFixedSource(t *testing.T) {
+	cfg := testConfig(t)
+	candidates := []string{"alice", "bob", "carol", "dave"}
+	got := orderCandidates(rand.New(rand.NewSource(1)), candidates, "author", cfg, nil)
+	want := orderCandidates(rand.New(rand.NewSource(1)), candidates, "author", cfg, nil)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orderCandidates with the same source = %v and %v, want the same order", got, want)
+	}
+	sorted := append([]string(nil), got...)
+	sort.Strings(sorted)
+	if !reflect.DeepEqual(sorted, candidates) {
+		t.Errorf("orderCandidates = %v, want a permutation of %v", got, candidates)
+	}
+}
+
+func TestAssignDefaultReviewersMinContributions(t *testing.T) {
+	tests := []struct {
+		name             string
+		minContributions int
+		want             []string
+	}{
+		{name: "default keeps every collaborator", minContributions: 1, want: []string{"bob", "Carol", "dave"}},
+		{name: "threshold", minContributions: 5, want: []string{"Carol", "dave"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Codeowners = false
+			cfg.MinContributions = tt.minContributions
+			client := newFakeClient()
+			client.repositories.collaborators = []string{"bob", "Carol", "dave", "deploy[bot]"}
+			client.repositories.contributions = map[string]int{"bob": 1, "carol": 12, "dave": 5, "deploy[bot]": 300}
+
+			if err := assignDefaultReviewers(context.Background(), testEnv(client.Client, cfg, nil), nil); err != nil {
+				t.Fatalf("assignDefaultReviewers: %v", err)
+			}
+			if len(client.pullRequests.requested) != 1 {
+				t.Fatalf("got %d review requests, want 1", len(client.pullRequests.requested))
+			}
+			got := client.pullRequests.requested[0].Reviewers
+			sort.Strings(got)
+			want := append([]string(nil), tt.want...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("requested %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAssignDefaultReviewersMergeConflict(t *testing.T) {
+	tests := []struct {
+		name          string
+		mergeable     *bool
+		state         string
+		labels        []string
+		wantRequested bool
+		wantAdded     []string
+		wantRemoved   []string
+	}{
+		{name: "conflicts", mergeable: github.Bool(false), state: "dirty", wantAdded: []string{"needs-rebase"}},
+		{name: "conflicts, already labeled", mergeable: github.Bool(false), state: "dirty", labels: []string{"needs-rebase"}},
+		{name: "not determined yet", state: "unknown", wantRequested: true},
+		{name: "resolved", mergeable: github.Bool(true), state: "clean", labels: []string{"needs-rebase"}, wantRequested: true, wantRemoved: []string{"needs-rebase"}},
+		{name: "blocked by checks", mergeable: github.Bool(false), state: "blocked", wantRequested: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Codeowners = false
+			cfg.SkipConflictedReviewers = true
+			client := newFakeClient()
+			client.repositories.collaborators = []string{"bob"}
+			env := testEnv(client.Client, cfg, labels(tt.labels...))
+			env.PR.Mergeable = tt.mergeable
+			env.PR.MergeableState = github.String(tt.state)
+
+			if err := assignDefaultReviewers(context.Background(), env, nil); err != nil {
+				t.Fatalf("assignDefaultReviewers: %v", err)
+			}
+			if requested := len(client.pullRequests.requested) != 0; requested != tt.wantRequested {
+				t.Errorf("requested reviewers = %v, want %v", requested, tt.wantRequested)
+			}
+			if !reflect.DeepEqual(client.issues.added, tt.wantAdded) {
+				t.Errorf("added %v, want %v", client.issues.added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(client.issues.removed, tt.wantRemoved) {
+				t.Errorf("removed %v, want %v", client.issues.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"math/rand"
+	"regexp"
+	"strings"
+)
+
+// TeamRoute sends reviews for PRs touching Paths to the members of a team.
+type TeamRoute struct {
+	Name    string   `json:"name" yaml:"name"`
+	Paths   []string `json:"paths" yaml:"paths"`
+	Members []string `json:"members" yaml:"members"`
+	// RoundRobin rotates through the members one at a time instead of requesting all of them.
+	RoundRobin bool `json:"roundRobin" yaml:"roundRobin"`
+	// Quorum is the number of approvals needed from this team. One extra member is requested
+	// so the quorum can still be met if someone is unavailable.
+	Quorum int `json:"quorum" yaml:"quorum"`
+	// Count is the number of members to request, e.g. one frontend reviewer per PR.
+	Count int `json:"count" yaml:"count"`
+}
+
+// requestCount returns how many members of the route to request; 0 means all of them.
+func (r TeamRoute) requestCount() int {
+	switch {
+	case r.Quorum > 0:
+		return r.Quorum + 1
+	case r.Count > 0:
+		return r.Count
+	case r.RoundRobin:
+		return 1
+	}
+	return 0
+}
+
+// matchTeamRoutes returns the routes whose path globs match at least one changed file.
+func matchTeamRoutes(routes []TeamRoute, files []*github.CommitFile) []TeamRoute {
+	var matched []TeamRoute
+	for _, route := range routes {
+		for _, file := range files {
+			if matchAnyGlob(route.Paths, file.GetFilename()) {
+				matched = append(matched, route)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// routeReviewers selects up to max reviewers (uncapped when negative) from every team whose
+// paths the PR touches. Round-robin teams contribute their next members in rotation, teams
+// with a quorum contribute enough members to meet it, teams with a count contribute that many
+// members, and other teams contribute all members. The picks of the first three kinds are
+// reserved before the all-members teams fill the remaining slots, so a quorum is only cut
+// short when max leaves no room for it. The author, ignored users, and exclude are never
+// picked. Members are sampled with rng. The returned rotation state must be saved once the
+// request succeeds.
+func routeReviewers(ctx context.Context, rng *rand.Rand, client *Client, owner, repo string, pr *github.PullRequest, files []*github.CommitFile, exclude []string, max int, cfg *Config) ([]string, rotationState) {
+	routes := matchTeamRoutes(cfg.TeamRoutes, files)
+	if len(routes) == 0 {
+		return nil, nil
+	}
+
+	var state rotationState
+	for _, route := range routes {
+		if route.RoundRobin {
+			state = loadRotationState(ctx, client, owner, repo, cfg.RoundRobinStateIssue)
+			break
+		}
+	}
+
+	author := pr.GetUser().GetLogin()
+	eligible := func(member string) bool {
+		return !strings.EqualFold(member, author) && !isIgnoredReviewer(member, cfg) && !containsFold(exclude, member)
+	}
+	seen := map[string]bool{}
+	var reserved, rest []string
+	add := func(list []string, members []string) []string {
+		for _, member := range members {
+			if key := strings.ToLower(member); !seen[key] {
+				seen[key] = true
+				list = append(list, member)
+			}
+		}
+		return list
+	}
+	for _, route := range routes {
+		n := route.requestCount()
+		if n == 0 {
+			continue
+		}
+		var picked []string
+		if route.RoundRobin {
+			picked = state.next(route, pr.GetNumber(), eligible, n)
+		} else {
+			picked = sampleMembers(rng, route.Members, eligible, n)
+		}
+		if len(picked) < route.Quorum {
+			loggerFrom(ctx).Infof("Team %s has only %d eligible members for a quorum of %d", route.Name, len(picked), route.Quorum)
+		}
+		reserved = add(reserved, picked)
+		loggerFrom(ctx).Infof("Team %s routed reviewers: %v", route.Name, picked)
+	}
+	if max >= 0 && len(reserved) > max {
+		loggerFrom(ctx).Warnf("Team routes reserve %d reviewers, more than MAX_REVIEWERS=%d; some teams won't get enough reviewers for their quorum", len(reserved), max)
+		return reserved[:max], state
+	}
+	for _, route := range routes {
+		if route.requestCount() != 0 {
+			continue
+		}
+		var picked []string
+		for _, member := range route.Members {
+			if eligible(member) {
+				picked = append(picked, member)
+			}
+		}
+		rest = add(rest, picked)
+		loggerFrom(ctx).Infof("Team %s routed reviewers: %v", route.Name, picked)
+	}
+	return capReviewers(append(reserved, rest...), max), state
+}
+
+// sampleMembers returns up to n randomly chosen eligible members.
+func sampleMembers(rng *rand.Rand, members []string, eligible func(string) bool, n int) []string {
+	var candidates []string
+	for _, m := range members {
+		if eligible(m) {
+			candidates = append(candidates, m)
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// rotationState records, per team, the index of the member to pick next.
+// A nil state is not persisted and derives its starting point from the PR number.
+type rotationState map[string]int
+
+// next returns up to n eligible members of the route in rotation order and advances the
+// team's position past them.
+func (s rotationState) next(route TeamRoute, prNumber int, eligible func(string) bool, n int) []string {
+	if len(route.Members) == 0 {
+		return nil
+	}
+	start := prNumber
+	if s != nil {
+		start = s[route.Name]
+	}
+	var picked []string
+	i := 0
+	for ; i < len(route.Members) && len(picked) < n; i++ {
+		member := route.Members[(start+i)%len(route.Members)]
+		if eligible(member) {
+			picked = append(picked, member)
+		}
+	}
+	if s != nil {
+		s[route.Name] = (start + i) % len(route.Members)
+	}
+	return picked
+}
+
+// rotationStateMarker prefixes the JSON state stored in the round-robin state issue.
+const rotationStateMarker = "<!-- auto-assign:round-robin -->"
+
+var rotationStatePattern = regexp.MustCompile("(?s)" + regexp.QuoteMeta(rotationStateMarker) + "\\s*```json\\s*(.*?)\\s*```")
+
+// loadRotationState reads the round-robin positions from the body of the state issue.
+// When no state issue is configured or it cannot be read, rotation falls back to the PR
+// number and is not persisted, so a failed read never overwrites the saved positions.
+func loadRotationState(ctx context.Context, client *Client, owner, repo string, issueNumber int) rotationState {
+	if issueNumber == 0 {
+		loggerFrom(ctx).Infof("ROUND_ROBIN_STATE_ISSUE not set, round-robin rotation is derived from the PR number")
+		return nil
+	}
+	state := rotationState{}
+	issue, err := getIssue(ctx, client, owner, repo, issueNumber)
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to read round-robin state from issue #%d: %v", issueNumber, err)
+		return nil
+	}
+	if m := rotationStatePattern.FindStringSubmatch(issue.GetBody()); m != nil {
+		if err := json.Unmarshal([]byte(m[1]), &state); err != nil {
+			loggerFrom(ctx).Warnf("Ignoring malformed round-robin state in issue #%d: %v", issueNumber, err)
+			return rotationState{}
+		}
+	}
+	return state
+}
+
+// saveRotationState writes the round-robin positions back to the state issue.
+func saveRotationState(ctx context.Context, client *Client, owner, repo string, issueNumber int, state rotationState, cfg *Config) {
+	if state == nil || issueNumber == 0 {
+		return
+	}
+	if cfg.DryRun {
+		loggerFrom(ctx).Infof("%s would save round-robin state to issue #%d", dryRunPrefix, issueNumber)
+		return
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to encode round-robin state: %v", err)
+		return
+	}
+	block := fmt.Sprintf("%s\n```json\n%s\n```", rotationStateMarker, data)
+
+	issue, err := getIssue(ctx, client, owner, repo, issueNumber)
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to read round-robin state issue #%d: %v", issueNumber, err)
+		return
+	}
+	body := issue.GetBody()
+	if rotationStatePattern.MatchString(body) {
+		body = rotationStatePattern.ReplaceAllLiteralString(body, block)
+	} else {
+		body = strings.TrimSpace(body + "\n\n" + block)
+	}
+	err = withRetry(ctx, func() error {
+		_, _, err := client.Issues.Edit(ctx, owner, repo, issueNumber, &github.IssueRequest{Body: &body})
+		return err
+	})
+	if err != nil {
+		loggerFrom(ctx).Warnf("Failed to save round-robin state to issue #%d: %v", issueNumber, err)
+	}
+}
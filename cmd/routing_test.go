@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"math/rand"
+	"net/http"
+	"testing"
+)
+
+func TestRouteReviewersCount(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.TeamRoutes = []TeamRoute{
+		{Name: "frontend", Paths: []string{"frontend/**"}, Members: []string{"alice", "bob", "author"}, Count: 1},
+		{Name: "backend", Paths: []string{"backend/**"}, Members: []string{"carol", "dave"}, Count: 1},
+		{Name: "docs", Paths: []string{"docs/**"}, Members: []string{"erin"}},
+	}
+	files := []*github.CommitFile{changedFile("frontend/app.ts", 1), changedFile("backend/main.go", 1)}
+	pr := testEnv(newFakeClient().Client, cfg, nil).PR
+
+	for i := 0; i < 20; i++ {
+		reviewers, _ := routeReviewers(context.Background(), rand.New(rand.NewSource(1)), nil, "o", "r", pr, files, nil, -1, cfg)
+		if len(reviewers) != 2 || !containsFold([]string{"alice", "bob"}, reviewers[0]) || !containsFold([]string{"carol", "dave"}, reviewers[1]) {
+			t.Fatalf("routeReviewers = %v, want one frontend and one backend reviewer", reviewers)
+		}
+	}
+}
+
+func TestRouteReviewersMixedRoutes(t *testing.T) {
+	all := TeamRoute{Name: "a", Paths: []string{"a/**"}, Members: []string{"a1", "a2", "a3"}}
+	quorum := TeamRoute{Name: "q", Paths: []string{"q/**"}, Members: []string{"b1", "b2", "b3", "b4"}, Quorum: 2}
+	files := []*github.CommitFile{changedFile("a/x.go", 1), changedFile("q/y.go", 1)}
+	tests := []struct {
+		name       string
+		routes     []TeamRoute
+		exclude    []string
+		ignored    []string
+		max        int
+		wantQuorum int
+		wantLen    int
+	}{
+		{name: "quorum reserved before all-members route", routes: []TeamRoute{all, quorum}, max: 4, wantQuorum: 3, wantLen: 4},
+		{name: "uncapped", routes: []TeamRoute{all, quorum}, max: -1, wantQuorum: 3, wantLen: 6},
+		{name: "reviewed members don't take quorum slots", routes: []TeamRoute{all, quorum}, exclude: []string{"B1", "b2"}, max: 4, wantQuorum: 2, wantLen: 4},
+		{name: "ignored members don't take quorum slots", routes: []TeamRoute{quorum}, ignored: []string{"b3"}, max: 4, wantQuorum: 3, wantLen: 3},
+		{name: "max below the quorum", routes: []TeamRoute{all, quorum}, max: 2, wantQuorum: 2, wantLen: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.TeamRoutes = tt.routes
+			cfg.IgnoredReviewers = tt.ignored
+			pr := testEnv(newFakeClient().Client, cfg, nil).PR
+
+			reviewers, _ := routeReviewers(context.Background(), rand.New(rand.NewSource(1)), nil, "o", "r", pr, files, tt.exclude, tt.max, cfg)
+			if len(reviewers) != tt.wantLen {
+				t.Fatalf("routeReviewers = %v, want %d reviewers", reviewers, tt.wantLen)
+			}
+			fromQuorum := 0
+			for _, r := range reviewers {
+				if containsFold(tt.exclude, r) || containsFold(tt.ignored, r) {
+					t.Errorf("routeReviewers = %v, picked excluded member %s", reviewers, r)
+				}
+				if containsFold(quorum.Members, r) {
+					fromQuorum++
+				}
+			}
+			if fromQuorum != tt.wantQuorum {
+				t.Errorf("routeReviewers = %v, want %d members of the quorum team", reviewers, tt.wantQuorum)
+			}
+		})
+	}
+}
+
+func TestRequestCount(t *testing.T) {
+	tests := []struct {
+		route TeamRoute
+		want  int
+	}{
+		{route: TeamRoute{}, want: 0},
+		{route: TeamRoute{RoundRobin: true}, want: 1},
+		{route: TeamRoute{Count: 2}, want: 2},
+		{route: TeamRoute{Count: 2, RoundRobin: true}, want: 2},
+		{route: TeamRoute{Count: 1, Quorum: 2}, want: 3},
+	}
+	for _, tt := range tests {
+		if got := tt.route.requestCount(); got != tt.want {
+			t.Errorf("%+v.requestCount() = %d, want %d", tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestLoadRotationStateUnreadable(t *testing.T) {
+	client := newFakeClient()
+	client.issues.issueErr = errorResponse(http.StatusBadGateway)
+	if state := loadRotationState(context.Background(), client.Client, "o", "r", 10); state != nil {
+		t.Errorf("loadRotationState = %v, want nil so the state is not saved", state)
+	}
+}
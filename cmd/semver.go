@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionManifest locates a version string in a changed file. Pattern must capture the
+// version as "major.minor.patch" in its first group.
+type VersionManifest struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+}
+
+// defaultVersionManifests covers npm packages and plain VERSION files.
+var defaultVersionManifests = []VersionManifest{
+	{Path: "package.json", Pattern: `"version"\s*:\s*"v?(\d+\.\d+\.\d+)`},
+	{Path: "VERSION", Pattern: `^\s*v?(\d+\.\d+\.\d+)`},
+}
+
+// Version bump levels, ordered by significance.
+const (
+	bumpNone = iota
+	bumpPatch
+	bumpMinor
+	bumpMajor
+)
+
+var bumpNames = map[int]string{bumpPatch: "patch", bumpMinor: "minor", bumpMajor: "major"}
+
+// handleSemverLabel adds a semver:<level> label when the PR bumps the version in a manifest.
+func handleSemverLabel(ctx context.Context, env *Env, files []*github.CommitFile) error {
+	cfg := env.Config
+	if !cfg.SemverLabels {
+		return nil
+	}
+
+	bump := detectBump(ctx, files, cfg)
+	if bump == bumpNone {
+		loggerFrom(ctx).Infof("No version bump detected")
+		return nil
+	}
+
+	label := cfg.SemverLabelPrefix + bumpNames[bump]
+
+	// Replace the label of a previously detected bump level.
+	var stale []string
+	for level, name := range bumpNames {
+		if level != bump {
+			stale = append(stale, cfg.SemverLabelPrefix+name)
+		}
+	}
+	removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, stale, cfg)
+
+	for _, l := range env.PR.Labels {
+		if l.GetName() == label {
+			loggerFrom(ctx).Infof("PR already has label: %s", label)
+			return nil
+		}
+	}
+
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{label}, "semver", cfg)
+}
+
+// detectBump returns the most significant version bump of the PR across VersionManifests.
+func detectBump(ctx context.Context, files []*github.CommitFile, cfg *Config) int {
+	bump := bumpNone
+	for _, manifest := range cfg.VersionManifests {
+		re, err := regexp.Compile(manifest.Pattern)
+		if err != nil {
+			loggerFrom(ctx).Warnf("Invalid version pattern for %s: %v", manifest.Path, err)
+			continue
+		}
+		for _, file := range files {
+			if !matchGlob(manifest.Path, file.GetFilename()) {
+				continue
+			}
+			if b := versionBump(file.GetPatch(), re); b > bump {
+				bump = b
+			}
+		}
+	}
+	return bump
+}
+
+// versionBump compares the versions on the removed and added lines of a unified diff patch
+// and returns the most significant component that increased.
+func versionBump(patch string, re *regexp.Regexp) int {
+	var oldVersion, newVersion []int
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			continue
+		case strings.HasPrefix(line, "-") && oldVersion == nil:
+			oldVersion = extractVersion(line[1:], re)
+		case strings.HasPrefix(line, "+") && newVersion == nil:
+			newVersion = extractVersion(line[1:], re)
+		}
+	}
+	if oldVersion == nil || newVersion == nil {
+		return bumpNone
+	}
+	for i, level := range []int{bumpMajor, bumpMinor, bumpPatch} {
+		if newVersion[i] > oldVersion[i] {
+			return level
+		}
+		if newVersion[i] < oldVersion[i] {
+			return bumpNone
+		}
+	}
+	return bumpNone
+}
+
+// extractVersion returns the [major, minor, patch] components captured by re, or nil.
+func extractVersion(line string, re *regexp.Regexp) []int {
+	m := re.FindStringSubmatch(line)
+	if len(m) < 2 {
+		return nil
+	}
+	parts := strings.SplitN(m[1], ".", 3)
+	if len(parts) != 3 {
+		return nil
+	}
+	version := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil
+		}
+		version[i] = n
+	}
+	return version
+}
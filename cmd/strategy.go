@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+	_ "time/tzdata" // The action image ships without a zoneinfo database.
+)
+
+// Reviewer selection strategies accepted by REVIEWER_STRATEGY.
+const (
+	strategyRandom   = "random"
+	strategyTimezone = "timezone"
+	strategyBalanced = "balanced"
+)
+
+// orderCandidates arranges reviewer candidates by priority tier, highest first, and within
+// each tier according to the configured strategy. Callers request a prefix of the result, so
+// a lower tier is only reached once the higher ones are exhausted, and the strategy only
+// decides who is asked when a tier overflows the cap. load holds the open review requests per
+// candidate for the balanced strategy. Random choices are drawn from rng.
+func orderCandidates(rng *rand.Rand, candidates []string, author string, cfg *Config, load map[string]int) []string {
+	var ordered []string
+	for _, tier := range groupByTier(candidates, cfg.ReviewerTiers) {
+		ordered = append(ordered, orderTier(rng, tier, author, cfg, load)...)
+	}
+	return ordered
+}
+
+// reviewerRand returns the source of the random reviewer choices for a PR, seeded with
+// ReviewerSeed or, when it is 0, with the PR number, so re-runs on a PR make the same choices.
+func reviewerRand(cfg *Config, prNumber int) *rand.Rand {
+	seed := cfg.ReviewerSeed
+	if seed == 0 {
+		seed = prNumber
+	}
+	return rand.New(rand.NewSource(int64(seed)))
+}
+
+// groupByTier splits candidates into the configured tiers, in tier order, followed by a final
+// group of candidates not listed in any tier. Empty groups are omitted.
+func groupByTier(candidates []string, tiers [][]string) [][]string {
+	tierOf := map[string]int{}
+	for i, tier := range tiers {
+		for _, login := range tier {
+			if _, ok := tierOf[login]; !ok {
+				tierOf[login] = i
+			}
+		}
+	}
+	groups := make([][]string, len(tiers)+1)
+	for _, c := range candidates {
+		i, ok := tierOf[c]
+		if !ok {
+			i = len(tiers)
+		}
+		groups[i] = append(groups[i], c)
+	}
+	var nonEmpty [][]string
+	for _, g := range groups {
+		if len(g) != 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
+
+// orderTier orders the candidates of a single tier according to the configured strategy.
+func orderTier(rng *rand.Rand, candidates []string, author string, cfg *Config, load map[string]int) []string {
+	ordered := append([]string(nil), candidates...)
+	switch cfg.ReviewerStrategy {
+	case strategyTimezone:
+		return orderByTimezone(rng, ordered, author, cfg, time.Now())
+	case strategyBalanced:
+		return orderByLoad(rng, ordered, load)
+	case strategyRandom, "":
+	default:
+		defaultLogger().Warnf("Unknown reviewer strategy %q, using random", cfg.ReviewerStrategy)
+	}
+	rng.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	return ordered
+}
+
+// orderByTimezone performs a weighted shuffle that favors candidates who are currently
+// within working hours and, secondarily, whose offset is close to the author's. Candidates
+// without timezone data keep the base weight, so they are still picked, just less often.
+func orderByTimezone(rng *rand.Rand, candidates []string, author string, cfg *Config, now time.Time) []string {
+	authorLoc := reviewerLocation(author, cfg)
+	weights := map[string]float64{}
+	for _, c := range candidates {
+		weight := 1.0
+		if loc := reviewerLocation(c, cfg); loc != nil {
+			if hour := now.In(loc).Hour(); hour >= cfg.WorkingHoursStart && hour < cfg.WorkingHoursEnd {
+				weight += 3
+			}
+			if authorLoc != nil && offsetHours(now, loc, authorLoc) <= 3 {
+				weight++
+			}
+		}
+		weights[c] = weight
+	}
+
+	// Efraimidis-Spirakis weighted sampling without replacement.
+	keys := map[string]float64{}
+	for _, c := range candidates {
+		keys[c] = math.Pow(rng.Float64(), 1/weights[c])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return keys[candidates[i]] > keys[candidates[j]]
+	})
+	return candidates
+}
+
+// orderByLoad orders candidates by their number of pending review requests, fewest first.
+// Candidates with the same load are shuffled.
+func orderByLoad(rng *rand.Rand, candidates []string, load map[string]int) []string {
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return load[candidates[i]] < load[candidates[j]]
+	})
+	return candidates
+}
+
+// reviewLoad counts the pending review requests of each user across the open PRs of the
+// repository. Listing the open PRs takes one call per 100 PRs, where searching per candidate
+// would quickly exhaust the search rate limit.
+func reviewLoad(ctx context.Context, client *Client, owner, repo string) map[string]int {
+	load := map[string]int{}
+	opts := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		var prs []*github.PullRequest
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			prs, resp, err = client.PullRequests.List(ctx, owner, repo, opts)
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to list open PRs for review load: %v", err)
+			return load
+		}
+		for _, pr := range prs {
+			for _, u := range pr.RequestedReviewers {
+				load[u.GetLogin()]++
+			}
+		}
+		if resp.NextPage == 0 {
+			return load
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// reviewerLocation returns the configured timezone of a user, or nil when unknown or invalid.
+func reviewerLocation(login string, cfg *Config) *time.Location {
+	for _, tz := range cfg.ReviewerTimezones {
+		if tz.Key != login {
+			continue
+		}
+		loc, err := time.LoadLocation(tz.Value)
+		if err != nil {
+			defaultLogger().Warnf("Invalid timezone %q for %s: %v", tz.Value, login, err)
+			return nil
+		}
+		return loc
+	}
+	return nil
+}
+
+// offsetHours returns the absolute difference in hours between two timezones at now.
+func offsetHours(now time.Time, a, b *time.Location) float64 {
+	_, offsetA := now.In(a).Zone()
+	_, offsetB := now.In(b).Zone()
+	return math.Abs(float64(offsetA-offsetB)) / 3600
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// summaryMarker identifies the sticky comment summarizing what the action changed.
+const summaryMarker = "<!-- auto-assign:summary -->"
+
+// runSummary collects the changes made to a PR during one run, and the reasons steps were
+// skipped or failed.
+type runSummary struct {
+	Labels    []string
+	Assignees []string
+	Reviewers []string
+	Milestone string
+	Skipped   []string
+	Failures  []string
+}
+
+// summaryKey is the context key of the run summary.
+type summaryKey struct{}
+
+// contextWithSummary returns a context whose writes are recorded in s.
+func contextWithSummary(ctx context.Context, s *runSummary) context.Context {
+	return context.WithValue(ctx, summaryKey{}, s)
+}
+
+// summaryFrom returns the run summary carried by ctx, or nil when changes aren't recorded.
+// A nil summary ignores records.
+func summaryFrom(ctx context.Context) *runSummary {
+	s, _ := ctx.Value(summaryKey{}).(*runSummary)
+	return s
+}
+
+// addLabels records added labels.
+func (s *runSummary) addLabels(labels ...string) {
+	if s != nil {
+		s.Labels = append(s.Labels, labels...)
+	}
+}
+
+// addAssignees records added assignees.
+func (s *runSummary) addAssignees(assignees ...string) {
+	if s != nil {
+		s.Assignees = append(s.Assignees, assignees...)
+	}
+}
+
+// addReviewers records requested reviewers.
+func (s *runSummary) addReviewers(reviewers ...string) {
+	if s != nil {
+		s.Reviewers = append(s.Reviewers, reviewers...)
+	}
+}
+
+// setMilestone records the milestone set.
+func (s *runSummary) setMilestone(title string) {
+	if s != nil {
+		s.Milestone = title
+	}
+}
+
+// addSkipped records why a step changed nothing.
+func (s *runSummary) addSkipped(format string, args ...any) {
+	if s != nil {
+		s.Skipped = append(s.Skipped, fmt.Sprintf(format, args...))
+	}
+}
+
+// addFailure records a failed handler.
+func (s *runSummary) addFailure(handler string, err error) {
+	if s != nil {
+		s.Failures = append(s.Failures, fmt.Sprintf("%s: %v", handler, err))
+	}
+}
+
+// empty reports whether the run changed nothing.
+func (s *runSummary) empty() bool {
+	return len(s.Labels) == 0 && len(s.Assignees) == 0 && len(s.Reviewers) == 0 && s.Milestone == ""
+}
+
+// body renders the summary as a comment body on the kind of object named by noun, such as
+// "pull request".
+func (s *runSummary) body(noun string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "auto-assign updated this %s:\n", noun)
+	if len(s.Labels) != 0 {
+		fmt.Fprintf(&b, "\n- Labels added: %s", formatList(s.Labels, "`", "`"))
+	}
+	if len(s.Assignees) != 0 {
+		fmt.Fprintf(&b, "\n- Assignees: %s", formatList(s.Assignees, "@", ""))
+	}
+	if len(s.Reviewers) != 0 {
+		fmt.Fprintf(&b, "\n- Reviewers requested: %s", formatList(s.Reviewers, "@", ""))
+	}
+	if s.Milestone != "" {
+		fmt.Fprintf(&b, "\n- Milestone: %s", s.Milestone)
+	}
+	return b.String()
+}
+
+// markdown renders the summary as a section of the job summary of the workflow run.
+func (s *runSummary) markdown(number int, dryRun bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### auto-assign: #%d", number)
+	if dryRun {
+		fmt.Fprintf(&b, " %s", dryRunPrefix)
+	}
+	b.WriteString("\n\n")
+	if len(s.Labels) != 0 {
+		fmt.Fprintf(&b, "- Labels added: %s\n", formatList(s.Labels, "`", "`"))
+	}
+	if len(s.Assignees) != 0 {
+		fmt.Fprintf(&b, "- Assignees: %s\n", formatList(s.Assignees, "", ""))
+	}
+	if len(s.Reviewers) != 0 {
+		fmt.Fprintf(&b, "- Reviewers requested: %s\n", formatList(s.Reviewers, "", ""))
+	}
+	if s.Milestone != "" {
+		fmt.Fprintf(&b, "- Milestone: %s\n", s.Milestone)
+	}
+	for _, reason := range s.Skipped {
+		fmt.Fprintf(&b, "- Skipped: %s\n", reason)
+	}
+	for _, failure := range s.Failures {
+		fmt.Fprintf(&b, "- Failed: %s\n", failure)
+	}
+	if s.empty() && len(s.Skipped) == 0 && len(s.Failures) == 0 {
+		b.WriteString("- No changes\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// writeStepSummary appends markdown to the job summary file named by GITHUB_STEP_SUMMARY,
+// which GitHub shows on the workflow run page. Outside of GitHub Actions nothing is written.
+func writeStepSummary(markdown string) {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		defaultLogger().Warnf("Failed to open job summary: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(markdown); err != nil {
+		defaultLogger().Warnf("Failed to write job summary: %v", err)
+	}
+}
+
+// writeSkippedStepSummary writes the job summary of a PR the pipeline didn't run for.
+func writeSkippedStepSummary(number int, reason string, cfg *Config) {
+	s := &runSummary{}
+	s.addSkipped("%s", reason)
+	writeStepSummary(s.markdown(number, cfg.DryRun))
+}
+
+// formatList wraps each item in prefix and suffix and joins them with commas.
+func formatList(items []string, prefix, suffix string) string {
+	formatted := make([]string, len(items))
+	for i, item := range items {
+		formatted[i] = prefix + item + suffix
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// postSummaryComment posts or updates the sticky summary comment. Runs that change nothing
+// leave the previous summary in place.
+func postSummaryComment(ctx context.Context, env *Env, s *runSummary) {
+	if s.empty() {
+		loggerFrom(ctx).Infof("Nothing changed, not updating the summary comment")
+		return
+	}
+	if env.Config.DryRun {
+		loggerFrom(ctx).Infof("%s would post summary comment", dryRunPrefix)
+		return
+	}
+	if err := upsertComment(ctx, env.Client, env.Owner, env.Repo, env.Number(), summaryMarker, s.body(env.noun())); err != nil {
+		loggerFrom(ctx).Warnf("Failed to post summary comment: %v", err)
+	} else {
+		loggerFrom(ctx).Infof("Posted summary comment")
+	}
+}
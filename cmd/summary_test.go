@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunSummaryMarkdown(t *testing.T) {
+	s := &runSummary{}
+	s.addLabels("feature", "D-3")
+	s.addAssignees("author")
+	s.addSkipped("reviewers: the PR already has reviewers")
+	s.addFailure("size", errors.New("list changed files: boom"))
+	want := "### auto-assign: #7\n\n" +
+		"- Labels added: `feature`, `D-3`\n" +
+		"- Assignees: author\n" +
+		"- Skipped: reviewers: the PR already has reviewers\n" +
+		"- Failed: size: list changed files: boom\n\n"
+	if got := s.markdown(7, false); got != want {
+		t.Errorf("markdown = %q, want %q", got, want)
+	}
+
+	want = "### auto-assign: #7 [dry-run]\n\n- No changes\n\n"
+	if got := (&runSummary{}).markdown(7, true); got != want {
+		t.Errorf("markdown of an empty dry run = %q, want %q", got, want)
+	}
+}
+
+func TestWriteStepSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+	writeStepSummary("first\n")
+	writeStepSummary("second\n")
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read job summary: %v", err)
+	}
+	if string(got) != "first\nsecond\n" {
+		t.Errorf("job summary = %q, want both sections appended", got)
+	}
+
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	writeStepSummary("ignored\n")
+}
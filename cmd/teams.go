@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+)
+
+// crossTeamFilter removes reviewer candidates who share a team with the PR author.
+// Team memberships are loaded lazily, once per run.
+type crossTeamFilter struct {
+	ctx    context.Context
+	client *Client
+	org    string
+	author string
+
+	loaded      bool
+	authorTeams map[string]bool
+	memberTeams map[string][]string
+}
+
+// newCrossTeamFilter returns a filter for the author's teams in org, or nil when disabled.
+func newCrossTeamFilter(ctx context.Context, client *Client, org, author string, cfg *Config) *crossTeamFilter {
+	if !cfg.CrossTeamReview {
+		return nil
+	}
+	return &crossTeamFilter{ctx: ctx, client: client, org: org, author: author}
+}
+
+// apply returns the candidates outside the author's teams. When that would leave nobody,
+// the unfiltered candidates are returned so review still happens.
+func (f *crossTeamFilter) apply(candidates []string) []string {
+	if f == nil || len(candidates) == 0 {
+		return candidates
+	}
+	f.load()
+	if len(f.authorTeams) == 0 {
+		return candidates
+	}
+
+	var filtered []string
+	for _, c := range candidates {
+		if !f.sharesTeam(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	if len(filtered) == 0 {
+		loggerFrom(f.ctx).Infof("All candidates share a team with %s, including same-team reviewers", f.author)
+		return candidates
+	}
+	loggerFrom(f.ctx).Infof("Excluded %d same-team candidates for cross-team review", len(candidates)-len(filtered))
+	return filtered
+}
+
+func (f *crossTeamFilter) sharesTeam(login string) bool {
+	for _, team := range f.memberTeams[login] {
+		if f.authorTeams[team] {
+			return true
+		}
+	}
+	return false
+}
+
+// load resolves the members of every team in the organization.
+func (f *crossTeamFilter) load() {
+	if f.loaded {
+		return
+	}
+	f.loaded = true
+	f.authorTeams = map[string]bool{}
+	f.memberTeams = map[string][]string{}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var teams []*github.Team
+		var resp *github.Response
+		err := withRetry(f.ctx, func() (err error) {
+			teams, resp, err = f.client.Teams.ListTeams(f.ctx, f.org, opts)
+			return err
+		})
+		if err != nil {
+			loggerFrom(f.ctx).Warnf("Failed to list teams of %s, cross-team review disabled: %v", f.org, err)
+			return
+		}
+		for _, team := range teams {
+			members, err := listTeamMembers(f.ctx, f.client, f.org, team.GetSlug())
+			if err != nil {
+				loggerFrom(f.ctx).Warnf("Failed to list members of team %s: %v", team.GetSlug(), err)
+				continue
+			}
+			for _, m := range members {
+				f.memberTeams[m] = append(f.memberTeams[m], team.GetSlug())
+				if m == f.author {
+					f.authorTeams[team.GetSlug()] = true
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// listTeamMembers returns the logins of all members of an organization team.
+func listTeamMembers(ctx context.Context, client *Client, org, slug string) ([]string, error) {
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var logins []string
+	for {
+		var members []*github.User
+		var resp *github.Response
+		err := withRetry(ctx, func() (err error) {
+			members, resp, err = client.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			logins = append(logins, m.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			return logins, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// checkedBoxPattern matches a checked markdown task list item, e.g. "- [x] high".
+var checkedBoxPattern = regexp.MustCompile(`(?i)^\s*[-*]\s*\[x\]\s*(.+)$`)
+
+// handleTemplateLabel maps the answer to a configured PR template question, such as
+// "Risk level: high", to a label. Missing or unfilled answers get the incomplete label;
+// answers without a mapped label get no label.
+func handleTemplateLabel(ctx context.Context, env *Env) error {
+	cfg := env.Config
+	if cfg.TemplateQuestion == "" {
+		return nil
+	}
+
+	var stale []string
+	var label string
+	answer, ok := templateAnswer(env.PR.GetBody(), cfg.TemplateQuestion)
+	if !ok {
+		loggerFrom(ctx).Infof("Template question %q is unanswered", cfg.TemplateQuestion)
+		label = cfg.TemplateIncompleteLabel
+	} else {
+		loggerFrom(ctx).Infof("Template question %q answered: %s", cfg.TemplateQuestion, answer)
+		stale = append(stale, cfg.TemplateIncompleteLabel)
+		if label = answerValue(cfg.TemplateAnswerLabels, answer); label == "" {
+			loggerFrom(ctx).Infof("No label is configured for answer %q", answer)
+		}
+	}
+	for _, rule := range cfg.TemplateAnswerLabels {
+		if rule.Value != label {
+			stale = append(stale, rule.Value)
+		}
+	}
+	removeManagedLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), env.PR.Labels, stale, cfg)
+
+	if label == "" {
+		return nil
+	}
+	for _, l := range env.PR.Labels {
+		if l.GetName() == label {
+			loggerFrom(ctx).Infof("PR already has label: %s", label)
+			return nil
+		}
+	}
+	return addLabels(ctx, env.Client, env.Owner, env.Repo, env.Number(), []string{label}, "template", cfg)
+}
+
+// templateAnswer finds question in a PR body and returns its normalized answer. The answer
+// may follow the question on the same line ("Risk level: high"), appear on the next line
+// under a heading, or be a checked task list item. Placeholders that list the options,
+// like "[low/medium/high]", count as unanswered.
+func templateAnswer(body, question string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	q := strings.ToLower(question)
+	for i, line := range lines {
+		idx := strings.Index(strings.ToLower(line), q)
+		if idx < 0 {
+			continue
+		}
+		answer := cleanAnswer(line[idx+len(question):])
+		for j := i + 1; answer == "" && j < len(lines); j++ {
+			next := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(next, "#") {
+				break
+			}
+			if m := checkedBoxPattern.FindStringSubmatch(next); m != nil {
+				answer = cleanAnswer(m[1])
+			} else if next != "" && !strings.HasPrefix(next, "<!--") && !strings.HasPrefix(next, "- [ ]") && !strings.HasPrefix(next, "* [ ]") {
+				answer = cleanAnswer(next)
+			}
+		}
+		if answer == "" || strings.ContainsAny(answer, "/|") {
+			return "", false
+		}
+		return answer, true
+	}
+	return "", false
+}
+
+// cleanAnswer strips markdown decoration and separators around an answer and lowercases it.
+func cleanAnswer(s string) string {
+	s = strings.Trim(strings.TrimSpace(s), ":*_`#>[]()\t ")
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// answerValue returns the value mapped to answer, matching keys case-insensitively.
+func answerValue(mapping []keyValue, answer string) string {
+	for _, m := range mapping {
+		if strings.EqualFold(m.Key, answer) {
+			return m.Value
+		}
+	}
+	return ""
+}
+
+// templateReviewerCount returns the reviewer count mapped to the template answer, or 0.
+func templateReviewerCount(pr *github.PullRequest, cfg *Config) int {
+	if cfg.TemplateQuestion == "" || len(cfg.TemplateAnswerReviewers) == 0 {
+		return 0
+	}
+	answer, ok := templateAnswer(pr.GetBody(), cfg.TemplateQuestion)
+	if !ok {
+		return 0
+	}
+	count, err := strconv.Atoi(answerValue(cfg.TemplateAnswerReviewers, answer))
+	if err != nil {
+		return 0
+	}
+	return count
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"github.com/google/go-github/v45/github"
+	"reflect"
+	"testing"
+)
+
+func TestHandleTemplateLabel(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		answerLabels []keyValue
+		labels       []string
+		wantAdded    []string
+		wantRemoved  []string
+	}{
+		{
+			name:         "mapped answer",
+			body:         "Risk level: high",
+			answerLabels: []keyValue{{Key: "high", Value: "risk:high"}, {Key: "low", Value: "risk:low"}},
+			labels:       []string{"template-incomplete", "risk:low"},
+			wantAdded:    []string{"risk:high"},
+			wantRemoved:  []string{"template-incomplete", "risk:low"},
+		},
+		{
+			name:         "unanswered",
+			body:         "Risk level: [low/high]",
+			answerLabels: []keyValue{{Key: "high", Value: "risk:high"}},
+			wantAdded:    []string{"template-incomplete"},
+		},
+		{
+			name:        "reviewers-only config",
+			body:        "Risk level: high",
+			labels:      []string{"template-incomplete"},
+			wantRemoved: []string{"template-incomplete"},
+		},
+	}
+	for _, tt := range tests {
+		client := newFakeClient()
+		cfg := testConfig(t)
+		cfg.TemplateQuestion = "Risk level"
+		cfg.TemplateAnswerLabels = tt.answerLabels
+		cfg.TemplateAnswerReviewers = []keyValue{{Key: "high", Value: "2"}}
+		env := testEnv(client.Client, cfg, labels(tt.labels...))
+		env.PR.Body = github.String(tt.body)
+
+		if err := handleTemplateLabel(context.Background(), env); err != nil {
+			t.Fatalf("%s: handleTemplateLabel: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(client.issues.added, tt.wantAdded) {
+			t.Errorf("%s: added %v, want %v", tt.name, client.issues.added, tt.wantAdded)
+		}
+		if !reflect.DeepEqual(client.issues.removed, tt.wantRemoved) {
+			t.Errorf("%s: removed %v, want %v", tt.name, client.issues.removed, tt.wantRemoved)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// validateTitle checks title hygiene. A title must always contain a colon separating the
+// prefix from the description; when cfg.RequireTitleDescription is set, the description
+// must also be at least cfg.MinTitleDescription characters long.
+func validateTitle(title string, cfg *Config) error {
+	_, description, ok := strings.Cut(title, ":")
+	if !ok {
+		return fmt.Errorf("title does not contain a colon: %s", title)
+	}
+	if !cfg.RequireTitleDescription {
+		return nil
+	}
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return fmt.Errorf("title has no description after the prefix: %s", title)
+	}
+	if n := utf8.RuneCountInString(description); n < cfg.MinTitleDescription {
+		return fmt.Errorf("title description is %d characters, want at least %d: %s", n, cfg.MinTitleDescription, title)
+	}
+	return nil
+}
+
+// scopePattern splits a conventional-commit header such as "feat(api)!", "feat/api", or
+// "feat[api]" into its type and scope.
+var scopePattern = regexp.MustCompile(`^([^(\[{</!]*)(?:[(\[{<]([^)\]}>]*)[)\]}>]?|/(.*?))?\s*!?$`)
+
+// extractPrefix parses the conventional-commit prefix of a title, e.g. "feat" and "api" for
+// "feat(api): add endpoint". Both are trimmed and lowercased. ok is false when the title has
+// no colon or no prefix before it. The description after the first colon may be empty.
+func extractPrefix(title string) (prefix, scope string, ok bool) {
+	header, _, found := strings.Cut(title, ":")
+	if !found {
+		return "", "", false
+	}
+	header = strings.ToLower(strings.TrimSpace(header))
+	m := scopePattern.FindStringSubmatch(header)
+	if m == nil {
+		return "", "", false
+	}
+	prefix = strings.TrimSpace(m[1])
+	if prefix == "" {
+		return "", "", false
+	}
+	return prefix, strings.TrimSpace(m[2] + m[3]), true
+}
+
+// resolveAlias returns the canonical prefix of an alternate prefix such as "bugfix" or
+// "Bug-Fix". A prefix the label map knows directly is returned as is, even when it is also
+// an alias.
+func resolveAlias(prefix string, labelMap map[string][]string, aliases map[string]string) string {
+	if _, ok := labelMap[prefix]; ok {
+		return prefix
+	}
+	if canonical, ok := aliases[normalizeAlias(prefix)]; ok {
+		return canonical
+	}
+	return prefix
+}
+
+// normalizeAlias lowercases a prefix and drops the separators contributors put in it, so
+// "bug-fix", "bug_fix", and "BugFix" are the same alias.
+func normalizeAlias(prefix string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "", ".", "").Replace(strings.ToLower(strings.TrimSpace(prefix)))
+}
+
+// addTitleLabel flags a PR or issue whose title could not be labeled normally, such as
+// the bad-title or triage label. kind names the label in log and error messages.
+func addTitleLabel(ctx context.Context, client *Client, owner, repo string, number int, labels []*github.Label, label, kind string, cfg *Config) error {
+	for _, l := range labels {
+		if l.GetName() == label {
+			loggerFrom(ctx).Infof("Already has label: %s", label)
+			return nil
+		}
+	}
+	return addLabels(ctx, client, owner, repo, number, []string{label}, kind, cfg)
+}
+
+// Title sources accepted by TITLE_SOURCE.
+const (
+	titleSourceTitle  = "title"
+	titleSourceBody   = "body"
+	titleSourceCommit = "commit"
+)
+
+// effectiveTitle returns the title to label against. Squash-merge repos may label against the
+// intended squash title, read from a body field or the first commit, instead of the informal
+// PR title. It falls back to the PR title when the configured source has nothing.
+func effectiveTitle(ctx context.Context, client *Client, owner, repo string, pr *github.PullRequest, cfg *Config) string {
+	switch cfg.TitleSource {
+	case titleSourceBody:
+		if title := bodyField(pr.GetBody(), cfg.SquashTitleField); title != "" {
+			loggerFrom(ctx).Infof("Using squash title from PR body: %s", title)
+			return title
+		}
+	case titleSourceCommit:
+		var commits []*github.RepositoryCommit
+		err := withRetry(ctx, func() (err error) {
+			commits, _, err = client.PullRequests.ListCommits(ctx, owner, repo, pr.GetNumber(), &github.ListOptions{PerPage: 1})
+			return err
+		})
+		if err != nil {
+			loggerFrom(ctx).Warnf("Failed to list PR commits: %v", err)
+		} else if len(commits) != 0 {
+			title, _, _ := strings.Cut(commits[0].GetCommit().GetMessage(), "\n")
+			if title = strings.TrimSpace(title); title != "" {
+				loggerFrom(ctx).Infof("Using squash title from first commit: %s", title)
+				return title
+			}
+		}
+	case titleSourceTitle, "":
+	default:
+		loggerFrom(ctx).Warnf("Unknown title source %q, using the PR title", cfg.TitleSource)
+	}
+	return pr.GetTitle()
+}
+
+// bodyField returns the value of a "Field: value" line in a PR body, ignoring markdown
+// emphasis around the field name, or "" when the field is absent or empty.
+func bodyField(body, field string) string {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "#*_> ")
+		if len(line) < len(field) || !strings.EqualFold(line[:len(field)], field) {
+			continue
+		}
+		rest := strings.TrimLeft(line[len(field):], "*_ ")
+		if value, ok := strings.CutPrefix(rest, ":"); ok {
+			return strings.TrimSpace(strings.TrimLeft(value, "*_ "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestExtractPrefix(t *testing.T) {
+	tests := []struct {
+		title       string
+		wantPrefix  string
+		wantScope   string
+		wantMatched bool
+	}{
+		{title: "feat: add endpoint", wantPrefix: "feat", wantMatched: true},
+		{title: "feat(api): add endpoint", wantPrefix: "feat", wantScope: "api", wantMatched: true},
+		{title: "feat/api: add endpoint", wantPrefix: "feat", wantScope: "api", wantMatched: true},
+		{title: "feat[api]: add endpoint", wantPrefix: "feat", wantScope: "api", wantMatched: true},
+		{title: "feat(api)!: drop v1", wantPrefix: "feat", wantScope: "api", wantMatched: true},
+		{title: "feat!: drop v1", wantPrefix: "feat", wantMatched: true},
+		{title: "Feat: Add", wantPrefix: "feat", wantMatched: true},
+		{title: "  FIX ( Core ) : trim", wantPrefix: "fix", wantScope: "core", wantMatched: true},
+		{title: "feat:", wantPrefix: "feat", wantMatched: true},
+		{title: "fix: handle a: b: c", wantPrefix: "fix", wantMatched: true},
+		{title: "fix:no space", wantPrefix: "fix", wantMatched: true},
+		{title: "feat : spaced colon", wantPrefix: "feat", wantMatched: true},
+		{title: "feat (api) !: spaced", wantPrefix: "feat", wantScope: "api", wantMatched: true},
+		{title: "feat(api: unclosed", wantPrefix: "feat", wantScope: "api", wantMatched: true},
+		{title: "feat<ui>: angle brackets", wantPrefix: "feat", wantScope: "ui", wantMatched: true},
+		{title: "feat{ui}: braces", wantPrefix: "feat", wantScope: "ui", wantMatched: true},
+		{title: "docs(): empty scope", wantPrefix: "docs", wantMatched: true},
+		{title: "feat/api/v2: nested scope", wantPrefix: "feat", wantScope: "api/v2", wantMatched: true},
+		{title: ""},
+		{title: ":"},
+		{title: "WIP messing around"},
+		{title: ": no prefix"},
+		{title: "(api): no type"},
+		{title: "!: no type"},
+		{title: "feat!!: doubled bang"},
+	}
+	for _, tt := range tests {
+		prefix, scope, ok := extractPrefix(tt.title)
+		if prefix != tt.wantPrefix || scope != tt.wantScope || ok != tt.wantMatched {
+			t.Errorf("extractPrefix(%q) = %q, %q, %v, want %q, %q, %v", tt.title, prefix, scope, ok, tt.wantPrefix, tt.wantScope, tt.wantMatched)
+		}
+	}
+}
+
+func TestValidateTitle(t *testing.T) {
+	cfg := testConfig(t)
+	if err := validateTitle("feat: x", cfg); err != nil {
+		t.Errorf("validateTitle without description requirement: %v", err)
+	}
+	if err := validateTitle("no colon", cfg); err == nil {
+		t.Error("validateTitle accepted a title without a colon")
+	}
+
+	cfg.RequireTitleDescription = true
+	for _, title := range []string{"feat:", "feat:   ", "feat: short"} {
+		if err := validateTitle(title, cfg); err == nil {
+			t.Errorf("validateTitle(%q) accepted a short description", title)
+		}
+	}
+	if err := validateTitle("feat: add the export button", cfg); err != nil {
+		t.Errorf("validateTitle: %v", err)
+	}
+}
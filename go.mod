@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/google/go-github/v45 v45.2.0
 	golang.org/x/oauth2 v0.0.0-20180821212333-d2e6202438be
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (